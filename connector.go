@@ -4,15 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"sync"
 	"time"
 
 	lunopb "github.com/cloudproud/lunodb.api/proto"
-	"github.com/cloudproud/lunodb.go/value"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
@@ -66,14 +67,77 @@ func WithToken(token string) ConnectorOption {
 	}
 }
 
+// WithSendQueueSize sets the number of outbound message batches that may be
+// queued before writers are blocked until the queue has been drained.
+func WithSendQueueSize(size int) ConnectorOption {
+	return func(connector *Connector) error {
+		if size < 1 {
+			return fmt.Errorf("invalid send queue size: %d", size)
+		}
+
+		connector.sendQueueSize = size
+		return nil
+	}
+}
+
+// WithStatementTimeout sets the maximum duration of a single statement. The
+// context passed to Handler.Scan is cancelled once the timeout expires. A zero
+// timeout disables the statement timeout.
+func WithStatementTimeout(timeout time.Duration) ConnectorOption {
+	return func(connector *Connector) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid statement timeout: %s", timeout)
+		}
+
+		connector.statementTimeout = timeout
+		return nil
+	}
+}
+
+// WithReconnectPolicy sets the policy used to reconnect to Stargate once the
+// connection has been closed.
+func WithReconnectPolicy(policy ReconnectPolicy) ConnectorOption {
+	return func(connector *Connector) error {
+		err := policy.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid reconnect policy: %w", err)
+		}
+
+		connector.reconnect = policy
+		return nil
+	}
+}
+
+// WithBatching sets the options used to buffer rows written during a scan
+// before they are handed to the sender.
+func WithBatching(options BatchOptions) ConnectorOption {
+	return func(connector *Connector) error {
+		err := options.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid batch options: %w", err)
+		}
+
+		connector.batch = options
+		return nil
+	}
+}
+
 // DefaultConnectorHeartbeat represents the default heartbeat interval in which a given
 // source controller will ping the configured source.
+//
+// Deprecated: the Connector no longer reconnects on a fixed interval and this
+// constant is unused. Use WithReconnectPolicy to configure the reconnect
+// backoff.
 const DefaultConnectorHeartbeat = 5 * time.Second
 
 // NewConnector constructs a new Connector instance with optional configuration overrides.
 func NewConnector(options ...ConnectorOption) (*Connector, error) {
 	connector := Connector{
 		logger:          zap.NewNop(),
+		sendQueueSize:   DefaultSendQueueSize,
+		reconnect:       DefaultReconnectPolicy,
+		batch:           DefaultBatchOptions,
+		shutdown:        make(chan struct{}),
 		StargateAddress: os.Getenv("LUNODB_STARGATE_ADDRESS"),
 		Insecure:        os.Getenv("LUNODB_INSECURE") == "true",
 	}
@@ -82,6 +146,13 @@ func NewConnector(options ...ConnectorOption) (*Connector, error) {
 		connector.StargateAddress = DefaultStargateAddress
 	}
 
+	config, err := tlsConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls configuration: %w", err)
+	}
+
+	connector.TLS = config
+
 	for _, option := range options {
 		err := option(&connector)
 		if err != nil {
@@ -96,13 +167,22 @@ func NewConnector(options ...ConnectorOption) (*Connector, error) {
 // maintains connection settings such as address, TLS preferences, and source
 // identity.
 type Connector struct {
-	logger          *zap.Logger
-	StargateAddress string
-	Insecure        bool
-	Source          uint64
-	Token           string
-	mu              sync.Mutex
-	healthy         bool
+	logger           *zap.Logger
+	StargateAddress  string
+	Insecure         bool
+	TLS              TLSConfig
+	Source           uint64
+	Token            string
+	sendQueueSize    int
+	statementTimeout time.Duration
+	reconnect        ReconnectPolicy
+	batch            BatchOptions
+	mu               sync.Mutex
+	healthy          bool
+	session          *session
+	tables           Tables
+	stopped          bool
+	shutdown         chan struct{}
 }
 
 // Healthy returns true if the Connector is currently healthy and able to
@@ -115,13 +195,22 @@ func (connector *Connector) Healthy() bool {
 }
 
 // Serve establishes a gRPC connection to the configured Stargate server and
-// starts the message receive loop using the provided handler. It blocks until
-// the stream ends or an error occurs.
+// starts the message receive loop using the provided handler. Closed
+// connections are reconnected according to the configured reconnect policy.
+// It blocks until the context is cancelled, the Connector has been shut down
+// or a *ReconnectError is returned once the reconnect policy gave up.
 func (connector *Connector) Serve(ctx context.Context, handler Handler) error {
 	options := []grpc.DialOption{}
 
 	if connector.Insecure {
 		options = append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		config, err := connector.TLS.Config()
+		if err != nil {
+			return fmt.Errorf("invalid tls configuration: %w", err)
+		}
+
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(config)))
 	}
 
 	conn, err := grpc.NewClient(connector.StargateAddress, options...)
@@ -143,56 +232,109 @@ func (connector *Connector) defaultOutgoingContext(ctx context.Context) context.
 }
 
 func (connector *Connector) serveLoop(ctx context.Context, client lunopb.StargateClient, handler Handler) error {
-	heartbeat := time.NewTicker(DefaultConnectorHeartbeat)
-	defer heartbeat.Stop()
+	policy := connector.reconnect
+	attempts := 0
 
 	for {
 		logger := connector.logger.With(zap.String("address", connector.StargateAddress))
 		logger.Info("attempting to connect to Stargate")
 
-		connector.serveTick(ctx, client, handler)
+		connected := time.Now()
+		err := connector.serveTick(ctx, client, handler)
+		if ctx.Err() != nil {
+			connector.logger.Info("context cancelled, stopping connector")
+			return nil
+		}
+
+		if connector.isShutdown() {
+			connector.logger.Info("connector shut down")
+			return nil
+		}
+
+		if policy.ResetAfter > 0 && time.Since(connected) >= policy.ResetAfter {
+			attempts = 0
+		}
+
+		if policy.MaxAttempts > 0 && attempts >= policy.MaxAttempts {
+			logger.Error("reconnect attempts exhausted", zap.Int("attempts", attempts), zap.Error(err))
+			return &ReconnectError{Attempts: attempts, Err: err}
+		}
 
-		logger.Info("connection closed, attempting to reconnect", zap.Duration("heartbeat", DefaultConnectorHeartbeat))
+		attempts++
+		backoff := policy.Backoff(attempts)
+		logger.Info("connection closed, attempting to reconnect", zap.Duration("backoff", backoff), zap.Int("attempt", attempts))
 
+		timer := time.NewTimer(backoff)
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			connector.logger.Info("context cancelled, stopping connector")
 			return nil
-		case <-heartbeat.C:
+		case <-connector.shutdown:
+			timer.Stop()
+			connector.logger.Info("connector shut down")
+			return nil
+		case <-timer.C:
 			logger.Info("attempting to reconnect to Stargate...")
 		}
 	}
 }
 
-func (connector *Connector) serveTick(ctx context.Context, client lunopb.StargateClient, handler Handler) {
-	ctx = connector.defaultOutgoingContext(ctx)
+func (connector *Connector) serveTick(ctx context.Context, client lunopb.StargateClient, handler Handler) error {
+	ctx, cancel := context.WithCancel(connector.defaultOutgoingContext(ctx))
+	defer cancel()
+
 	stream, err := client.Connector(ctx)
 	if err != nil {
 		connector.logger.Error("failed to connect to Stargate", zap.Error(err))
-		return
+		return err
 	}
 
 	connector.logger.Info("connected to Stargate")
-	err = connector.recvLoop(ctx, stream, handler)
+	err = connector.recvLoop(ctx, &session{
+		stream:     stream,
+		sender:     newSender(stream, connector.sendQueueSize),
+		statements: newStatements(connector.statementTimeout),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}, handler)
 	if err != nil {
 		connector.logger.Error("unexpected error in receive loop", zap.Error(err))
-		return
+		return err
 	}
+
+	return nil
 }
 
-func (connector *Connector) recvLoop(ctx context.Context, stream grpc.BidiStreamingClient[lunopb.ConnectorResponse, lunopb.ConnectorRequest], handler Handler) error {
-	connector.health(true)
-	defer connector.health(false)
+func (connector *Connector) recvLoop(ctx context.Context, session *session, handler Handler) error {
+	defer close(session.done)
+
+	if !connector.attach(session) {
+		return nil
+	}
+
+	defer connector.detach()
 
 	logger := connector.logger.With(zap.String("address", connector.StargateAddress))
 	logger.Info("starting message receive loop")
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	sender := session.sender
+	statements := session.statements
+	go func() {
+		err := sender.run(ctx)
+		if err != nil {
+			logger.Error("failed to send message", zap.Error(err))
+			session.cancel()
+		}
+	}()
 
 	for {
-		msg, err := stream.Recv()
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		msg, err := session.stream.Recv()
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return nil
+		}
+
+		if errors.Is(err, io.EOF) && connector.isShutdown() {
 			return nil
 		}
 
@@ -205,40 +347,74 @@ func (connector *Connector) recvLoop(ctx context.Context, stream grpc.BidiStream
 		switch state := msg.State.(type) {
 		case *lunopb.ConnectorRequest_Ping:
 			go func() {
-				err := connector.ping(ctx, msg.Id, stream, handler)
+				err := connector.ping(ctx, msg.Id, sender, handler)
 				if err != nil {
 					logger.Error("failed to ping", zap.Error(err))
-					cancel()
 				}
 			}()
 		case *lunopb.ConnectorRequest_Fetch:
 			go func() {
-				err := connector.fetch(ctx, msg.Id, stream, handler)
+				err := connector.fetch(ctx, msg.Id, sender, handler)
 				if err != nil {
 					logger.Error("failed to fetch tables", zap.Error(err))
-					cancel()
 				}
 			}()
 		case *lunopb.ConnectorRequest_ExecuteStatement:
+			statement, done, err := statements.start(ctx)
+			if err != nil {
+				go func() {
+					err := connector.reject(ctx, msg.Id, err, sender)
+					if err != nil {
+						logger.Error("failed to reject statement", zap.Error(err))
+					}
+				}()
+
+				continue
+			}
+
 			go func() {
-				err := connector.execute(ctx, msg.Id, state.ExecuteStatement, stream, handler)
+				defer done()
+
+				err := connector.execute(ctx, statement, msg.Id, state.ExecuteStatement, sender, handler)
 				if err != nil {
 					logger.Error("failed to execute statement", zap.Error(err))
-					cancel()
 				}
 			}()
 		}
 	}
 }
 
-func (connector *Connector) health(status bool) {
+// attach marks the given session as the active session and the Connector as
+// healthy. False is returned if the Connector has been shut down.
+func (connector *Connector) attach(session *session) bool {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+
+	if connector.stopped {
+		return false
+	}
+
+	connector.session = session
+	connector.healthy = true
+	return true
+}
+
+func (connector *Connector) detach() {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+
+	connector.session = nil
+	connector.healthy = false
+}
+
+func (connector *Connector) isShutdown() bool {
 	connector.mu.Lock()
 	defer connector.mu.Unlock()
 
-	connector.healthy = status
+	return connector.stopped
 }
 
-func (connector *Connector) ping(ctx context.Context, id uint32, stream grpc.BidiStreamingClient[lunopb.ConnectorResponse, lunopb.ConnectorRequest], handler Handler) error {
+func (connector *Connector) ping(ctx context.Context, id uint32, sender *sender, handler Handler) error {
 	logger := connector.logger.With(zap.Uint32("id", id))
 	logger.Debug("ping connector")
 
@@ -252,7 +428,7 @@ func (connector *Connector) ping(ctx context.Context, id uint32, stream grpc.Bid
 	}
 
 	logger.Debug("ping complete")
-	return stream.Send(&lunopb.ConnectorResponse{
+	return sender.Send(ctx, &lunopb.ConnectorResponse{
 		Id: id,
 		State: &lunopb.ConnectorResponse_Ping{
 			Ping: pong,
@@ -260,7 +436,7 @@ func (connector *Connector) ping(ctx context.Context, id uint32, stream grpc.Bid
 	})
 }
 
-func (connector *Connector) fetch(ctx context.Context, id uint32, stream grpc.BidiStreamingClient[lunopb.ConnectorResponse, lunopb.ConnectorRequest], handler Handler) error {
+func (connector *Connector) fetch(ctx context.Context, id uint32, sender *sender, handler Handler) error {
 	logger := connector.logger.With(zap.Uint32("id", id))
 	logger.Debug("fetching tables")
 
@@ -277,8 +453,14 @@ func (connector *Connector) fetch(ctx context.Context, id uint32, stream grpc.Bi
 		fetch.Tables = tables.Proto()
 	}
 
+	if err == nil {
+		connector.mu.Lock()
+		connector.tables = tables
+		connector.mu.Unlock()
+	}
+
 	logger.Debug("tables fetched", zap.Int("count", len(fetch.Tables)))
-	return stream.Send(&lunopb.ConnectorResponse{
+	return sender.Send(ctx, &lunopb.ConnectorResponse{
 		Id: id,
 		State: &lunopb.ConnectorResponse_Fetch{
 			Fetch: fetch,
@@ -286,55 +468,70 @@ func (connector *Connector) fetch(ctx context.Context, id uint32, stream grpc.Bi
 	})
 }
 
-func (connector *Connector) execute(ctx context.Context, id uint32, state *lunopb.ExecuteStatementRequest, stream grpc.BidiStreamingClient[lunopb.ConnectorResponse, lunopb.ConnectorRequest], handler Handler) error {
+// schema returns the tables most recently fetched from the given handler.
+// The tables are fetched if they have not been fetched before.
+func (connector *Connector) schema(ctx context.Context, handler Handler) (Tables, error) {
+	connector.mu.Lock()
+	tables := connector.tables
+	connector.mu.Unlock()
+
+	if tables != nil {
+		return tables, nil
+	}
+
+	tables, err := handler.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tables: %w", err)
+	}
+
+	connector.mu.Lock()
+	connector.tables = tables
+	connector.mu.Unlock()
+
+	return tables, nil
+}
+
+func (connector *Connector) execute(ctx context.Context, statement context.Context, id uint32, state *lunopb.ExecuteStatementRequest, sender *sender, handler Handler) error {
 	plan := state.Plan
 
 	logger := connector.logger.With(zap.Uint32("id", id))
 	logger.Debug("executing statement")
 
-	writer := WriterFunc(func(ctx context.Context, values []any) (err error) {
-		row := make([][]byte, len(values))
-		for index, col := range values {
-			_, row[index], err = value.Encode(col, nil)
-			if err != nil {
-				return err
-			}
-		}
+	tables, err := connector.schema(statement, handler)
+	if err != nil {
+		logger.Error("unexpected error while fetching tables", zap.Error(err))
+		return connector.reject(ctx, id, err, sender)
+	}
 
-		logger.Debug("writing row")
-		return stream.Send(&lunopb.ConnectorResponse{
-			Id: id,
-			State: &lunopb.ConnectorResponse_ExecuteStatement{
-				ExecuteStatement: &lunopb.ExecuteStatementResponse{
-					Result: &lunopb.ExecuteStatementResponse_Data{
-						Data: &lunopb.Row{
-							Values: row,
-						},
-					},
-				},
-			},
-		})
-	})
+	err = validatePlan(plan, tables)
+	if err != nil {
+		logger.Debug("rejecting plan", zap.Error(err))
+		return connector.reject(ctx, id, err, sender)
+	}
+
+	writer := newStatementWriter(statement, id, sender, connector.batch, logger)
+	if table, ok := tables.Find(plan.GetFrom().GetSchema(), plan.GetFrom().GetTable()); ok {
+		writer.table = &table
+	}
+
+	writer.columns = outputColumns(plan, tables)
+	writer.names = outputNames(plan)
+	writer.project, writer.width = outputProjection(plan, tables)
+
+	// NOTE: handlers could return nil once the statement context is cancelled,
+	// the rows written so far might be incomplete.
+	err = handler.Scan(statement, plan, writer)
+	if statement.Err() != nil {
+		err = context.Cause(statement)
+	}
 
-	err := handler.Scan(ctx, plan, writer)
 	if err != nil {
 		logger.Error("unexpected error while scanning", zap.Error(err))
-		return stream.Send(&lunopb.ConnectorResponse{
-			Id: id,
-			State: &lunopb.ConnectorResponse_ExecuteStatement{
-				ExecuteStatement: &lunopb.ExecuteStatementResponse{
-					Result: &lunopb.ExecuteStatementResponse_Error{
-						Error: &lunopb.Error{
-							Message: err.Error(),
-						},
-					},
-				},
-			},
-		})
+		return writer.close(ctx, executeError(id, err))
 	}
 
 	logger.Debug("statement executed successfully")
-	return stream.Send(&lunopb.ConnectorResponse{
+	return writer.close(ctx, &lunopb.ConnectorResponse{
 		Id: id,
 		State: &lunopb.ConnectorResponse_ExecuteStatement{
 			ExecuteStatement: &lunopb.ExecuteStatementResponse{
@@ -343,3 +540,26 @@ func (connector *Connector) execute(ctx context.Context, id uint32, state *lunop
 		},
 	})
 }
+
+// reject reports the given error as the result of the statement with the
+// given request id.
+func (connector *Connector) reject(ctx context.Context, id uint32, err error, sender *sender) error {
+	return sender.Send(ctx, executeError(id, err))
+}
+
+// executeError constructs a new execute statement response reporting the
+// given error.
+func executeError(id uint32, err error) *lunopb.ConnectorResponse {
+	return &lunopb.ConnectorResponse{
+		Id: id,
+		State: &lunopb.ConnectorResponse_ExecuteStatement{
+			ExecuteStatement: &lunopb.ExecuteStatementResponse{
+				Result: &lunopb.ExecuteStatementResponse_Error{
+					Error: &lunopb.Error{
+						Message: err.Error(),
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package lunodbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/types"
+	"go.uber.org/zap"
+)
+
+// testHandler is a handler scanning using the given function.
+type testHandler struct {
+	tables Tables
+	scan   func(ctx context.Context, plan *plan.Literal, writer Writer) error
+}
+
+func (handler *testHandler) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (handler *testHandler) Fetch(ctx context.Context) (Tables, error) {
+	return handler.tables, nil
+}
+
+func (handler *testHandler) Scan(ctx context.Context, plan *plan.Literal, writer Writer) error {
+	return handler.scan(ctx, plan, writer)
+}
+
+var testTables = Tables{{
+	Name:   "weather",
+	Schema: "public",
+	Columns: Columns{
+		{Name: "city", Type: types.BasicString},
+	},
+}}
+
+var testPlan = &plan.Literal{
+	From: &plan.From{Schema: "public", Table: "weather"},
+}
+
+// executeRequest constructs a new execute statement request for the test
+// plan.
+func executeRequest(id uint32) *lunopb.ConnectorRequest {
+	return &lunopb.ConnectorRequest{
+		Id: id,
+		State: &lunopb.ConnectorRequest_ExecuteStatement{
+			ExecuteStatement: &lunopb.ExecuteStatementRequest{Plan: testPlan},
+		},
+	}
+}
+
+func TestExecuteCancelledStatement(t *testing.T) {
+	ctx := context.Background()
+	connector, err := NewConnector(WithLogger(zap.NewNop()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	stream := newTestStream(ctx)
+	sender := newSender(stream, DefaultSendQueueSize)
+	go sender.run(ctx) //nolint:errcheck
+
+	statements := newStatements(10 * time.Millisecond)
+	statement, done, err := statements.start(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	defer done()
+
+	handler := &testHandler{
+		tables: testTables,
+		scan: func(ctx context.Context, _ *plan.Literal, writer Writer) error {
+			err := writer.Write(ctx, []any{"Amsterdam"})
+			if err != nil {
+				return err
+			}
+
+			<-ctx.Done()
+			return nil
+		},
+	}
+
+	err = connector.execute(ctx, statement, 1, executeRequest(1).GetExecuteStatement(), sender, handler)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = sender.close(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sent := stream.sent()
+	if len(sent) == 0 {
+		t.Fatal("expected a result to be sent")
+	}
+
+	result := sent[len(sent)-1].GetExecuteStatement()
+	if result.GetError().GetMessage() != ErrStatementTimeout.Error() {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
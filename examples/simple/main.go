@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 
 	"github.com/cloudproud/lunodb.api/proto/plan"
 	lunodb "github.com/cloudproud/lunodb.go"
+	"github.com/cloudproud/lunodb.go/planutil"
 	"github.com/cloudproud/lunodb.go/types"
 	"go.uber.org/zap"
 )
@@ -83,9 +86,39 @@ type WeatherResponse struct {
 }
 
 func (c *Connector) Scan(ctx context.Context, plan *plan.Literal, writer lunodb.Writer) error {
-	city := "Amsterdam"
-	endpoint := fmt.Sprintf("https://wttr.in/%s?format=j1", city)
-	res, err := http.Get(endpoint)
+	query, err := planutil.Parse(plan)
+	if err != nil {
+		return err
+	}
+
+	cities, ok := query.Equal("city")
+	if !ok {
+		return errors.New("city is required")
+	}
+
+	for _, city := range cities {
+		name, ok := city.(string)
+		if !ok {
+			return fmt.Errorf("unexpected city: %v", city)
+		}
+
+		err := c.scan(ctx, name, writer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Connector) scan(ctx context.Context, city string, writer lunodb.Writer) error {
+	endpoint := fmt.Sprintf("https://wttr.in/%s?format=j1", url.PathEscape(city))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -99,6 +132,10 @@ func (c *Connector) Scan(ctx context.Context, plan *plan.Literal, writer lunodb.
 		return err
 	}
 
+	if len(weather.CurrentCondition) == 0 {
+		return nil
+	}
+
 	current := weather.CurrentCondition[0]
-	return writer.Write(ctx, []any{city, current.TempC, current.Humidity})
+	return lunodb.WriteFull(ctx, writer, []any{city, current.TempC, current.Humidity})
 }
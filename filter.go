@@ -0,0 +1,700 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/planutil"
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+// FilterOption defines a functional option for configuring a Filter.
+type FilterOption func(*Filter) error
+
+// WithApplied marks the given predicates as applied by the handler, these are
+// not evaluated by the filter.
+func WithApplied(predicates ...planutil.Predicate) FilterOption {
+	return func(filter *Filter) error {
+		for _, predicate := range predicates {
+			filter.applied = append(filter.applied, predicate.Expression)
+		}
+
+		return nil
+	}
+}
+
+// WithFilterTable sets the table declaring the types of the filtered columns.
+// By default the table targeted by the statement is used when writing to the
+// statement writer.
+func WithFilterTable(table Table) FilterOption {
+	return func(filter *Filter) error {
+		filter.table = &table
+		return nil
+	}
+}
+
+// WithFilterColumns sets the names of the columns of the rows written to the
+// filter in order. By default the columns projected by the plan are used.
+func WithFilterColumns(columns ...string) FilterOption {
+	return func(filter *Filter) error {
+		filter.columns = columns
+		return nil
+	}
+}
+
+// Filter is a Writer evaluating the WHERE clause of a plan against every
+// written row. Only rows matching the filter are written to the underlying
+// writer, allowing handlers to push down only the predicates supported by
+// their data source. Conjunctions which have been marked as applied are not
+// evaluated. Comparisons follow SQL semantics, comparisons against NULL never
+// match unless IS [NOT] DISTINCT FROM is used. Written values are coerced
+// into the declared column types before being compared.
+//
+// Predicates are resolved against the full table row if the table is
+// declared. Rows written using Write are only matched if every filtered column
+// is written, rows written using WriteFull are always matched.
+type Filter struct {
+	writer  Writer
+	columns []string
+	table   *Table
+	applied []*plan.FilterExpression
+	eval    condition
+	full    condition
+}
+
+// errNotWritten is returned when a filtered column is not written.
+var errNotWritten = errors.New("column is not written")
+
+// tableWriter is implemented by writers aware of the table targeted by the
+// statement.
+type tableWriter interface {
+	declaredTable() (Table, bool)
+}
+
+// NewFilter constructs a new filter evaluating the filter of the given plan
+// against the rows written to the given writer. An error is returned if the
+// remaining predicates could not be evaluated, such as predicates referencing
+// columns which are neither declared nor written or using unsupported
+// expressions.
+func NewFilter(literal *plan.Literal, writer Writer, options ...FilterOption) (*Filter, error) {
+	filter := &Filter{
+		writer:  writer,
+		columns: Projection(writer),
+	}
+
+	if filter.columns == nil {
+		filter.columns = planutil.Columns(literal)
+	}
+
+	if writer, ok := writer.(tableWriter); ok {
+		if table, ok := writer.declaredTable(); ok {
+			filter.table = &table
+		}
+	}
+
+	for _, option := range options {
+		err := option(filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var remaining []*plan.FilterExpression
+	for _, conjunction := range conjunctions(literal.GetFilter()) {
+		if !slices.Contains(filter.applied, conjunction) {
+			remaining = append(remaining, conjunction)
+		}
+	}
+
+	eval, unwritten := filter.compileAll(remaining, filter.columns)
+	if unwritten != nil && (filter.table == nil || !errors.Is(unwritten, errNotWritten)) {
+		return nil, unwritten
+	}
+
+	if unwritten != nil {
+		eval = unavailable(fmt.Errorf("%w, write full rows using WriteFull", unwritten))
+	}
+
+	filter.eval = eval
+	if filter.table == nil {
+		return filter, nil
+	}
+
+	names := make([]string, len(filter.table.Columns))
+	for index, column := range filter.table.Columns {
+		names[index] = column.Name
+	}
+
+	full, err := filter.compileAll(remaining, names)
+	if err != nil && (unwritten != nil || !errors.Is(err, errNotWritten)) {
+		return nil, err
+	}
+
+	if err != nil {
+		full = unavailable(fmt.Errorf("%w, write projected rows using Write", err))
+	}
+
+	filter.full = full
+	return filter, nil
+}
+
+// compileAll compiles the given conjunctions against rows containing the
+// given columns.
+func (filter *Filter) compileAll(conjunctions []*plan.FilterExpression, columns []string) (condition, error) {
+	conditions := make([]condition, 0, len(conjunctions))
+	for _, conjunction := range conjunctions {
+		cond, err := filter.compile(conjunction, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, cond)
+	}
+
+	return and(conditions...), nil
+}
+
+// Write writes the given row to the underlying writer if it matches the
+// filter. An error is returned if any of the filtered columns is not written.
+func (filter *Filter) Write(ctx context.Context, values []any) error {
+	ok, err := filter.Match(values)
+	if err != nil || !ok {
+		return err
+	}
+
+	return filter.writer.Write(ctx, values)
+}
+
+// WriteRow writes the given encoded row to the underlying writer if it
+// matches the filter.
+func (filter *Filter) WriteRow(ctx context.Context, row *value.RowEncoder) error {
+	values, err := row.Values()
+	if err != nil {
+		return err
+	}
+
+	ok, err := filter.Match(values)
+	if err != nil || !ok {
+		return err
+	}
+
+	return WriteRow(ctx, filter.writer, row)
+}
+
+// WriteFull writes the given row containing all table columns in their
+// declared order to the underlying writer if it matches the filter.
+func (filter *Filter) WriteFull(ctx context.Context, values []any) error {
+	if filter.full == nil {
+		return errors.New("unable to filter full rows without a declared table")
+	}
+
+	if len(values) != len(filter.table.Columns) {
+		return fmt.Errorf("row contains %d values while the table declares %d columns", len(values), len(filter.table.Columns))
+	}
+
+	result, err := filter.full(values)
+	if err != nil || result != truthTrue {
+		return err
+	}
+
+	return WriteFull(ctx, filter.writer, values)
+}
+
+// positions returns the positions of the named columns within the given
+// columns. Nil is returned if any of the names is not declared.
+func positions(columns Columns, names []string) []int {
+	result := make([]int, len(names))
+	for index, name := range names {
+		result[index] = slices.IndexFunc(columns, func(column Column) bool {
+			return column.Name == name
+		})
+
+		if result[index] < 0 {
+			return nil
+		}
+	}
+
+	return result
+}
+
+// Match returns true if the given row, containing the filtered columns,
+// matches the filter.
+func (filter *Filter) Match(values []any) (bool, error) {
+	if len(values) != len(filter.columns) {
+		return false, fmt.Errorf("row contains %d values while %d columns are filtered", len(values), len(filter.columns))
+	}
+
+	result, err := filter.eval(values)
+	return result == truthTrue, err
+}
+
+// Columns returns the names of the filtered columns.
+func (filter *Filter) Columns() []string {
+	return filter.columns
+}
+
+// Flush flushes the underlying writer.
+func (filter *Filter) Flush(ctx context.Context) error {
+	return Flush(ctx, filter.writer)
+}
+
+// truth represents the result of a SQL condition.
+type truth uint8
+
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+func truthOf(ok bool) truth {
+	if ok {
+		return truthTrue
+	}
+
+	return truthFalse
+}
+
+func (result truth) not() truth {
+	switch result {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	default:
+		return truthUnknown
+	}
+}
+
+type condition func(row []any) (truth, error)
+
+type operand func(row []any) (any, error)
+
+func and(conditions ...condition) condition {
+	return func(row []any) (truth, error) {
+		result := truthTrue
+		for _, cond := range conditions {
+			val, err := cond(row)
+			if err != nil {
+				return truthFalse, err
+			}
+
+			if val == truthFalse {
+				return truthFalse, nil
+			}
+
+			if val == truthUnknown {
+				result = truthUnknown
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// unavailable returns a condition failing with the given error, used for rows
+// lacking the filtered columns.
+func unavailable(err error) condition {
+	return func([]any) (truth, error) {
+		return truthFalse, err
+	}
+}
+
+func or(conditions ...condition) condition {
+	return func(row []any) (truth, error) {
+		result := truthFalse
+		for _, cond := range conditions {
+			val, err := cond(row)
+			if err != nil {
+				return truthFalse, err
+			}
+
+			if val == truthTrue {
+				return truthTrue, nil
+			}
+
+			if val == truthUnknown {
+				result = truthUnknown
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// compile compiles the given filter expression into a condition evaluated
+// against rows containing the given columns.
+func (filter *Filter) compile(expr *plan.FilterExpression, columns []string) (condition, error) {
+	switch {
+	case expr.GetAndExpression() != nil:
+		return filter.compileBinary(expr.GetAndExpression().Left, expr.GetAndExpression().Right, columns, and)
+	case expr.GetOrExpression() != nil:
+		return filter.compileBinary(expr.GetOrExpression().Left, expr.GetOrExpression().Right, columns, or)
+	case expr.GetComparisonExpression() != nil:
+		return filter.compileComparison(expr.GetComparisonExpression(), columns)
+	case expr.GetExpression() != nil:
+		operand, err := filter.operand(expr.GetExpression(), columns)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(row []any) (truth, error) {
+			val, err := operand(row)
+			if err != nil || value.IsNull(val) {
+				return truthUnknown, err
+			}
+
+			ok, isBool := reflect.Indirect(reflect.ValueOf(val)).Interface().(bool)
+			if !isBool {
+				return truthFalse, fmt.Errorf("cannot use %T as condition", val)
+			}
+
+			return truthOf(ok), nil
+		}, nil
+	}
+
+	return nil, errors.New("unable to evaluate empty filter expression")
+}
+
+func (filter *Filter) compileBinary(left *plan.FilterExpression, right *plan.FilterExpression, columns []string, combine func(...condition) condition) (condition, error) {
+	lhs, err := filter.compile(left, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rhs, err := filter.compile(right, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return combine(lhs, rhs), nil
+}
+
+// operand compiles the given expression into an operand evaluated against
+// rows containing the given columns. Columns, constants, casts and tuples are
+// supported.
+func (filter *Filter) operand(expr *plan.Expression, columns []string) (operand, error) {
+	switch {
+	case expr.GetColumn() != nil:
+		name := expr.GetColumn().Name
+		index := slices.Index(columns, name)
+		if index < 0 {
+			return nil, fmt.Errorf("unable to filter on column %q: %w", name, errNotWritten)
+		}
+
+		var typ *lunopb.Type
+		if filter.table != nil {
+			column, ok := filter.table.Columns.Find(name)
+			if ok {
+				typ = column.Type
+			}
+		}
+
+		return func(row []any) (any, error) {
+			val, err := value.Coerce(typ, row[index])
+			if err != nil {
+				return nil, fmt.Errorf("column %q: %w", name, err)
+			}
+
+			return val, nil
+		}, nil
+	case expr.GetConstant() != nil:
+		val, _, err := value.Decode(expr.GetConstant().Type, expr.GetConstant().Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constant: %w", err)
+		}
+
+		return func([]any) (any, error) {
+			return val, nil
+		}, nil
+	case expr.GetCastExpression() != nil:
+		cast := expr.GetCastExpression()
+		inner, err := filter.operand(cast.Expression, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(row []any) (any, error) {
+			val, err := inner(row)
+			if err != nil {
+				return nil, err
+			}
+
+			return value.Coerce(cast.Type, val)
+		}, nil
+	case expr.GetTuple() != nil:
+		items := make([]operand, len(expr.GetTuple().Expressions))
+		for index, item := range expr.GetTuple().Expressions {
+			var err error
+			items[index], err = filter.operand(item, columns)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return func(row []any) (any, error) {
+			result := make([]any, len(items))
+			for index, item := range items {
+				val, err := item(row)
+				if err != nil {
+					return nil, err
+				}
+
+				result[index] = val
+			}
+
+			return result, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unable to evaluate expression: %s", expr.String())
+}
+
+func (filter *Filter) compileComparison(comparison *plan.ComparisonExpression, columns []string) (condition, error) {
+	left, err := filter.operand(comparison.Left.GetExpression(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := filter.operand(comparison.Right.GetExpression(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	compare, err := comparator(comparison.Operator)
+	if err != nil {
+		return nil, err
+	}
+
+	switch comparison.SubOperator {
+	case node.OperatorUnknown:
+	case node.Any, node.All:
+		compare = quantified(compare, comparison.SubOperator == node.All)
+	default:
+		return nil, fmt.Errorf("unable to evaluate sub operator %s", comparison.SubOperator)
+	}
+
+	return func(row []any) (truth, error) {
+		lhs, err := left(row)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		rhs, err := right(row)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return compare(lhs, rhs)
+	}, nil
+}
+
+type comparison func(left any, right any) (truth, error)
+
+// comparator returns the comparison of the given operator.
+func comparator(operator node.OperatorStatement) (comparison, error) {
+	switch operator {
+	case node.Equal:
+		return ordered(func(result int) bool { return result == 0 }), nil
+	case node.NotEqual:
+		return ordered(func(result int) bool { return result != 0 }), nil
+	case node.GreaterThan:
+		return ordered(func(result int) bool { return result > 0 }), nil
+	case node.GreaterOrEqualThan:
+		return ordered(func(result int) bool { return result >= 0 }), nil
+	case node.LessThan:
+		return ordered(func(result int) bool { return result < 0 }), nil
+	case node.LessOrEqualThan:
+		return ordered(func(result int) bool { return result <= 0 }), nil
+	case node.In:
+		return quantified(ordered(func(result int) bool { return result == 0 }), false), nil
+	case node.NotIn:
+		return negated(quantified(ordered(func(result int) bool { return result == 0 }), false)), nil
+	case node.Like:
+		return matching(likePattern, false), nil
+	case node.NotLike:
+		return negated(matching(likePattern, false)), nil
+	case node.ILike:
+		return matching(likePattern, true), nil
+	case node.NotILike:
+		return negated(matching(likePattern, true)), nil
+	case node.RegMatch:
+		return matching(regexPattern, false), nil
+	case node.NotRegMatch:
+		return negated(matching(regexPattern, false)), nil
+	case node.RegIMatch:
+		return matching(regexPattern, true), nil
+	case node.NotRegIMatch:
+		return negated(matching(regexPattern, true)), nil
+	case node.IsDistinctFrom:
+		return distinct, nil
+	case node.IsNotDistinctFrom:
+		return negated(distinct), nil
+	}
+
+	return nil, fmt.Errorf("unable to evaluate operator %s", Statement(operator))
+}
+
+// ordered compares both values using value.Compare. Comparisons against NULL
+// are unknown.
+func ordered(matches func(result int) bool) comparison {
+	return func(left any, right any) (truth, error) {
+		if value.IsNull(left) || value.IsNull(right) {
+			return truthUnknown, nil
+		}
+
+		result, err := value.Compare(left, right)
+		if err != nil {
+			return truthFalse, err
+		}
+
+		return truthOf(matches(result)), nil
+	}
+}
+
+func negated(compare comparison) comparison {
+	return func(left any, right any) (truth, error) {
+		result, err := compare(left, right)
+		return result.not(), err
+	}
+}
+
+// quantified compares the left value against every item of the right list,
+// matching if any (or all) of the items match.
+func quantified(compare comparison, all bool) comparison {
+	return func(left any, right any) (truth, error) {
+		if value.IsNull(right) {
+			return truthUnknown, nil
+		}
+
+		rv := reflect.Indirect(reflect.ValueOf(right))
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return truthFalse, fmt.Errorf("cannot use %T as list", right)
+		}
+
+		conditions := make([]condition, rv.Len())
+		for index := range rv.Len() {
+			item := rv.Index(index).Interface()
+			conditions[index] = func([]any) (truth, error) {
+				return compare(left, item)
+			}
+		}
+
+		if all {
+			return and(conditions...)(nil)
+		}
+
+		return or(conditions...)(nil)
+	}
+}
+
+// distinct returns true if the values are distinct, treating NULL as a
+// comparable value.
+func distinct(left any, right any) (truth, error) {
+	if value.IsNull(left) || value.IsNull(right) {
+		return truthOf(value.IsNull(left) != value.IsNull(right)), nil
+	}
+
+	result, err := value.Compare(left, right)
+	if err != nil {
+		return truthFalse, err
+	}
+
+	return truthOf(result != 0), nil
+}
+
+// matching matches the left string against the right pattern. Patterns are
+// compiled once and recompiled whenever the pattern changes.
+func matching(compile func(pattern string, insensitive bool) (*regexp.Regexp, error), insensitive bool) comparison {
+	var mutex sync.Mutex
+	var pattern string
+	var expr *regexp.Regexp
+
+	return func(left any, right any) (truth, error) {
+		if value.IsNull(left) || value.IsNull(right) {
+			return truthUnknown, nil
+		}
+
+		str, ok := stringOf(left)
+		if !ok {
+			return truthFalse, fmt.Errorf("cannot match %T against a pattern", left)
+		}
+
+		raw, ok := stringOf(right)
+		if !ok {
+			return truthFalse, fmt.Errorf("cannot use %T as pattern", right)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if expr == nil || raw != pattern {
+			compiled, err := compile(raw, insensitive)
+			if err != nil {
+				return truthFalse, err
+			}
+
+			pattern, expr = raw, compiled
+		}
+
+		return truthOf(expr.MatchString(str)), nil
+	}
+}
+
+func stringOf(val any) (string, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	if rv.Kind() != reflect.String {
+		return "", false
+	}
+
+	return rv.String(), true
+}
+
+// likePattern compiles the given LIKE pattern. The % wildcard matches any
+// sequence of characters and _ matches a single character, wildcards are
+// escaped using a backslash.
+func likePattern(pattern string, insensitive bool) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	builder.WriteString("(?s)^")
+	if insensitive {
+		builder.WriteString("(?i)")
+	}
+
+	escaped := false
+	for _, char := range pattern {
+		switch {
+		case escaped:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '%':
+			builder.WriteString(".*")
+		case char == '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
+
+// regexPattern compiles the given regular expression.
+func regexPattern(pattern string, insensitive bool) (*regexp.Regexp, error) {
+	if insensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
@@ -0,0 +1,173 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/types"
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+func valueExpr(tb testing.TB, val any) *plan.Expression {
+	typ, buf, err := value.Encode(val, nil)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	return constantExpr(typ, buf)
+}
+
+// collect returns a writer collecting all written rows.
+func collect(rows *[][]any) Writer {
+	return WriterFunc(func(_ context.Context, values []any) error {
+		*rows = append(*rows, values)
+		return nil
+	})
+}
+
+func TestFilterOperators(t *testing.T) {
+	tests := []struct {
+		operator node.OperatorStatement
+		constant any
+		val      any
+		expected bool
+	}{
+		{operator: node.Equal, constant: "a", val: "a", expected: true},
+		{operator: node.NotEqual, constant: "a", val: "a", expected: false},
+		{operator: node.Equal, constant: "a", val: nil, expected: false},
+		{operator: node.NotEqual, constant: "a", val: nil, expected: false},
+		{operator: node.GreaterThan, constant: int64(3), val: int32(4), expected: true},
+		{operator: node.LessOrEqualThan, constant: int64(3), val: int64(4), expected: false},
+		{operator: node.In, constant: []string{"x", "y"}, val: "y", expected: true},
+		{operator: node.NotIn, constant: []string{"x", "y"}, val: "z", expected: true},
+		{operator: node.Like, constant: "ab%_", val: "abcd", expected: true},
+		{operator: node.Like, constant: "ab%_", val: "ab", expected: false},
+		{operator: node.NotLike, constant: "a\\%", val: "a%", expected: false},
+		{operator: node.ILike, constant: "AB%", val: "abc", expected: true},
+		{operator: node.NotILike, constant: "AB%", val: "abc", expected: false},
+		{operator: node.RegMatch, constant: "^a.c$", val: "abc", expected: true},
+		{operator: node.NotRegMatch, constant: "^A.C$", val: "abc", expected: true},
+		{operator: node.RegIMatch, constant: "^A.C$", val: "abc", expected: true},
+		{operator: node.NotRegIMatch, constant: "^A.C$", val: "abc", expected: false},
+		{operator: node.IsDistinctFrom, constant: "a", val: nil, expected: true},
+		{operator: node.IsNotDistinctFrom, constant: "a", val: "a", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(node.OperatorStatement(test.operator).String(), func(t *testing.T) {
+			var rows [][]any
+			literal := &plan.Literal{Filter: compareFilter(test.operator, columnExpr("column"), valueExpr(t, test.constant))}
+
+			filter, err := NewFilter(literal, collect(&rows), WithFilterColumns("column"))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = filter.Write(context.Background(), []any{test.val})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if (len(rows) == 1) != test.expected {
+				t.Errorf("unexpected match of %#v against %#v: %t", test.val, test.constant, len(rows) == 1)
+			}
+		})
+	}
+}
+
+func TestFilterCoercesDeclaredTypes(t *testing.T) {
+	table := Table{Columns: Columns{
+		{Name: "day", Type: types.BasicDate},
+		{Name: "id", Type: types.BasicUUID},
+	}}
+
+	id, err := value.ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	literal := &plan.Literal{Filter: &plan.FilterExpression{Condition: &plan.FilterExpression_AndExpression{AndExpression: &plan.AndExpression{
+		Left:  compareFilter(node.Equal, columnExpr("day"), valueExpr(t, value.NewDate(2024, 2, 29))),
+		Right: compareFilter(node.Equal, columnExpr("id"), valueExpr(t, id)),
+	}}}}
+
+	var rows [][]any
+	filter, err := NewFilter(literal, collect(&rows), WithFilterColumns("day", "id"), WithFilterTable(table))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	day := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	err = filter.Write(context.Background(), []any{day, "6ba7b810-9dad-11d1-80b4-00c04fd430c8"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(rows) != 1 {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
+
+func TestFilterWriteFull(t *testing.T) {
+	table := Table{Columns: weatherColumns}
+	literal := &plan.Literal{Filter: compareFilter(node.GreaterThan, columnExpr("temperature"), valueExpr(t, int64(10)))}
+
+	var rows [][]any
+	filter, err := NewFilter(literal, collect(&rows), WithFilterColumns("humidity", "temperature"), WithFilterTable(table))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, row := range [][]any{{"Amsterdam", int64(12), 80.5}, {"Utrecht", int64(8), 60.5}} {
+		err := filter.WriteFull(context.Background(), row)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if len(rows) != 1 || rows[0][0] != "Amsterdam" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
+
+func TestFilterUnprojectedColumn(t *testing.T) {
+	table := Table{Columns: weatherColumns}
+	literal := &plan.Literal{Filter: compareFilter(node.GreaterThan, columnExpr("temperature"), valueExpr(t, int64(10)))}
+
+	var rows [][]any
+	filter, err := NewFilter(literal, collect(&rows), WithFilterColumns("city"), WithFilterTable(table))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = filter.Write(context.Background(), []any{"Amsterdam"})
+	if !errors.Is(err, errNotWritten) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, row := range [][]any{{"Amsterdam", int64(12), 80.5}, {"Utrecht", int64(8), 60.5}} {
+		err := filter.WriteFull(context.Background(), row)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if len(rows) != 1 || rows[0][0] != "Amsterdam" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	_, err = NewFilter(literal, collect(&rows), WithFilterColumns("city"))
+	if !errors.Is(err, errNotWritten) {
+		t.Errorf("unexpected error without a declared table: %v", err)
+	}
+
+	literal.Filter = compareFilter(node.GreaterThan, columnExpr("pressure"), valueExpr(t, int64(10)))
+	_, err = NewFilter(literal, collect(&rows), WithFilterColumns("city"), WithFilterTable(table))
+	if !errors.Is(err, errNotWritten) {
+		t.Errorf("unexpected error for an undeclared column: %v", err)
+	}
+}
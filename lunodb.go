@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/value"
 )
 
 const DefaultStargateAddress = "stargate.lunodb.io"
@@ -28,7 +29,9 @@ type Handler interface {
 // It is typically called once per matching result row.
 type Writer interface {
 	// Write sends a single row of values, typically from a Scan implementation.
-	// The values should match the output schema defined in the query plan.
+	// The values should match the output schema defined in the query plan. Rows
+	// which do not match the projected columns are rejected with an error. A nil
+	// value represents NULL and is only accepted for nullable columns.
 	Write(ctx context.Context, values []any) error
 }
 
@@ -39,3 +42,82 @@ type WriterFunc func(ctx context.Context, values []any) error
 func (fn WriterFunc) Write(ctx context.Context, values []any) error {
 	return fn(ctx, values)
 }
+
+// Flusher is implemented by writers which buffer rows before sending them to
+// Stargate. Flush sends all buffered rows.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Flush sends all rows buffered by the given writer. It is a no-op if the
+// writer does not implement Flusher.
+func Flush(ctx context.Context, writer Writer) error {
+	flusher, ok := writer.(Flusher)
+	if !ok {
+		return nil
+	}
+
+	return flusher.Flush(ctx)
+}
+
+// ColumnWriter is implemented by writers aware of the columns projected by the
+// query plan. Columns returns the projected column names in order.
+type ColumnWriter interface {
+	Writer
+	Columns() []string
+}
+
+// Projection returns the names of the columns projected by the query plan in
+// order, allowing handlers to skip fetching columns which are not projected.
+// Nil is returned if the writer is unaware of the projected columns.
+func Projection(writer Writer) []string {
+	columns, ok := writer.(ColumnWriter)
+	if !ok {
+		return nil
+	}
+
+	return columns.Columns()
+}
+
+// RowWriter is implemented by writers accepting rows which have already been
+// encoded using a value.RowEncoder, avoiding the allocations of Write.
+type RowWriter interface {
+	WriteRow(ctx context.Context, row *value.RowEncoder) error
+}
+
+// WriteRow writes the given encoded row. The row values are decoded and passed
+// to Write if the writer does not implement RowWriter. The row encoder could
+// be reset or released once WriteRow returns.
+func WriteRow(ctx context.Context, writer Writer, row *value.RowEncoder) error {
+	rows, ok := writer.(RowWriter)
+	if ok {
+		return rows.WriteRow(ctx, row)
+	}
+
+	values, err := row.Values()
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, values)
+}
+
+// FullWriter is implemented by writers aware of the table targeted by the
+// query plan. WriteFull accepts rows containing all table columns in their
+// declared order and reduces them to the projected columns. Rows passed to
+// Write always contain the projected columns.
+type FullWriter interface {
+	WriteFull(ctx context.Context, values []any) error
+}
+
+// WriteFull writes the given row containing all table columns in their
+// declared order. The row is passed to Write as is if the writer does not
+// implement FullWriter.
+func WriteFull(ctx context.Context, writer Writer, values []any) error {
+	full, ok := writer.(FullWriter)
+	if ok {
+		return full.WriteFull(ctx, values)
+	}
+
+	return writer.Write(ctx, values)
+}
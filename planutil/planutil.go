@@ -0,0 +1,381 @@
+// Package planutil inspects the query plans handed to a connector during a
+// scan. It extracts the targeted table, the projected columns and the
+// predicates comparing columns against constants, allowing connectors to push
+// filters down into their data source.
+package planutil
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+// Query represents the parts of a query plan relevant to a connector.
+type Query struct {
+	// Schema and Table represent the targeted table.
+	Schema string
+	Table  string
+	// Columns represents the names of the projected columns in order.
+	// Projected expressions which are not columns are represented by an empty
+	// name.
+	Columns []string
+	// Predicates represents the comparisons between a column and constant
+	// values which all have to hold for a row to match.
+	Predicates []Predicate
+	// Residual represents the conjunctions of the filter which could not be
+	// represented as a predicate, such as OR expressions and function calls.
+	Residual []*plan.FilterExpression
+	// Order represents the ORDER BY expressions in order.
+	Order []Order
+}
+
+// Predicate represents a comparison between a column and constant values.
+type Predicate struct {
+	Column   string
+	Operator node.OperatorStatement
+	// Values represents the decoded constants, a single value for comparison
+	// operators and a value per item for IN and NOT IN. NULL constants are
+	// represented as nil.
+	Values []any
+	// Expression represents the filter expression the predicate was
+	// extracted from.
+	Expression *plan.FilterExpression
+}
+
+// Value returns the first constant of the predicate.
+func (predicate Predicate) Value() any {
+	if len(predicate.Values) == 0 {
+		return nil
+	}
+
+	return predicate.Values[0]
+}
+
+// Direction represents the sort direction of an ORDER BY expression.
+type Direction uint32
+
+// NOTE: the plan carries the direction as a plain integer, ascending being
+// the zero value.
+const (
+	Ascending Direction = iota
+	Descending
+)
+
+func (direction Direction) String() string {
+	switch direction {
+	case Ascending:
+		return "ASC"
+	case Descending:
+		return "DESC"
+	default:
+		return fmt.Sprintf("Direction(%d)", uint32(direction))
+	}
+}
+
+// Order represents a single ORDER BY expression. Column is empty if the
+// expression is not a column.
+type Order struct {
+	Column     string
+	Direction  Direction
+	Expression *plan.Expression
+}
+
+// Parse inspects the given plan. An error is returned if a constant within
+// the plan could not be decoded.
+func Parse(literal *plan.Literal) (*Query, error) {
+	query := &Query{
+		Schema:  literal.GetFrom().GetSchema(),
+		Table:   literal.GetFrom().GetTable(),
+		Columns: Columns(literal),
+	}
+
+	err := query.filter(literal.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, expr := range literal.GetOrderBy().GetExpressions() {
+		query.Order = append(query.Order, Order{
+			Column:     expr.GetExpression().GetColumn().GetName(),
+			Direction:  Direction(expr.GetDirection()),
+			Expression: expr.GetExpression(),
+		})
+	}
+
+	return query, nil
+}
+
+// Columns returns the names of the columns projected by the given plan in
+// order. Projected expressions which are not columns are returned as an empty
+// name.
+func Columns(literal *plan.Literal) []string {
+	columns := make([]string, len(literal.GetColumns()))
+	for index, expr := range literal.GetColumns() {
+		columns[index] = expr.GetColumn().GetName()
+	}
+
+	return columns
+}
+
+// Projected returns true if the given column is projected by the query.
+func (query *Query) Projected(column string) bool {
+	return slices.Contains(query.Columns, column)
+}
+
+// filter collects the predicates of the conjunctions within the given filter.
+func (query *Query) filter(filter *plan.FilterExpression) error {
+	if filter == nil {
+		return nil
+	}
+
+	if and := filter.GetAndExpression(); and != nil {
+		err := query.filter(and.Left)
+		if err != nil {
+			return err
+		}
+
+		return query.filter(and.Right)
+	}
+
+	predicate, ok, err := predicateOf(filter)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		query.Residual = append(query.Residual, filter)
+		return nil
+	}
+
+	query.Predicates = append(query.Predicates, predicate)
+	return nil
+}
+
+// predicateOf returns the predicate represented by the given filter. False is
+// returned if the filter does not compare a column against constants.
+func predicateOf(filter *plan.FilterExpression) (Predicate, bool, error) {
+	if column := filter.GetExpression().GetColumn(); column != nil {
+		return Predicate{Column: column.Name, Operator: node.Equal, Values: []any{true}, Expression: filter}, true, nil
+	}
+
+	comparison := filter.GetComparisonExpression()
+	if comparison == nil {
+		return Predicate{}, false, nil
+	}
+
+	operator := comparison.Operator
+	left, right := comparison.Left.GetExpression(), comparison.Right.GetExpression()
+	if left.GetColumn() == nil {
+		flipped, ok := Flip(operator)
+		if !ok || comparison.SubOperator != node.OperatorUnknown {
+			return Predicate{}, false, nil
+		}
+
+		operator, left, right = flipped, right, left
+	}
+
+	if left.GetColumn() == nil {
+		return Predicate{}, false, nil
+	}
+
+	switch {
+	case comparison.SubOperator == node.Any && operator == node.Equal:
+		operator = node.In
+	case comparison.SubOperator != node.OperatorUnknown:
+		return Predicate{}, false, nil
+	}
+
+	var values []any
+	var ok bool
+	var err error
+
+	switch operator {
+	case node.In, node.NotIn:
+		values, ok, err = constants(right)
+	default:
+		var val any
+		val, ok, err = constant(right)
+		values = []any{val}
+	}
+
+	if err != nil {
+		return Predicate{}, false, fmt.Errorf("column %q: %w", left.GetColumn().Name, err)
+	}
+
+	if !ok {
+		return Predicate{}, false, nil
+	}
+
+	return Predicate{
+		Column:     left.GetColumn().Name,
+		Operator:   operator,
+		Values:     values,
+		Expression: filter,
+	}, true, nil
+}
+
+// Flip returns the equivalent of the given comparison operator with its
+// operands swapped (e.g. > for <). False is returned if the operator has no
+// such equivalent.
+func Flip(operator node.OperatorStatement) (node.OperatorStatement, bool) {
+	flipped, ok := flip[operator]
+	return flipped, ok
+}
+
+// flip maps comparison operators onto their equivalent with the operands
+// swapped.
+var flip = map[node.OperatorStatement]node.OperatorStatement{
+	node.Equal:              node.Equal,
+	node.NotEqual:           node.NotEqual,
+	node.GreaterThan:        node.LessThan,
+	node.GreaterOrEqualThan: node.LessOrEqualThan,
+	node.LessThan:           node.GreaterThan,
+	node.LessOrEqualThan:    node.GreaterOrEqualThan,
+	node.IsDistinctFrom:     node.IsDistinctFrom,
+	node.IsNotDistinctFrom:  node.IsNotDistinctFrom,
+}
+
+// constant decodes the given constant expression. Casts are unwrapped. False
+// is returned if the expression is not a constant.
+func constant(expr *plan.Expression) (any, bool, error) {
+	for expr.GetCastExpression() != nil {
+		expr = expr.GetCastExpression().Expression
+	}
+
+	constant := expr.GetConstant()
+	if constant == nil {
+		return nil, false, nil
+	}
+
+	val, _, err := value.Decode(constant.Type, constant.Value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+// constants decodes the given tuple of constants or constant array. False is
+// returned if any of the items is not a constant.
+func constants(expr *plan.Expression) ([]any, bool, error) {
+	tuple := expr.GetTuple()
+	if tuple == nil {
+		val, ok, err := constant(expr)
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+
+		values, ok := val.([]any)
+		return values, ok, nil
+	}
+
+	values := make([]any, len(tuple.Expressions))
+	for index, item := range tuple.Expressions {
+		val, ok, err := constant(item)
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+
+		values[index] = val
+	}
+
+	return values, true, nil
+}
+
+// Find returns the predicates of the given column.
+func (query *Query) Find(column string) []Predicate {
+	var result []Predicate
+	for _, predicate := range query.Predicates {
+		if predicate.Column == column {
+			result = append(result, predicate)
+		}
+	}
+
+	return result
+}
+
+// Equal returns the constants the given column has to equal, as constrained
+// by =, IS NOT DISTINCT FROM or IN predicates. False is returned if the column
+// is not constrained. The first constraining predicate is used when multiple
+// are present.
+func (query *Query) Equal(column string) ([]any, bool) {
+	for _, predicate := range query.Find(column) {
+		switch predicate.Operator {
+		case node.Equal, node.IsNotDistinctFrom, node.In:
+			return predicate.Values, true
+		}
+	}
+
+	return nil, false
+}
+
+// Bound represents a single bound of a range.
+type Bound struct {
+	Value     any
+	Inclusive bool
+}
+
+// Range represents the range of values a column is constrained to. A nil
+// bound represents an unbounded side.
+type Range struct {
+	Lower *Bound
+	Upper *Bound
+}
+
+// Range returns the range the given column is constrained to by comparison
+// and equality predicates. The tightest bounds are returned when multiple
+// predicates constrain the same side. False is returned if the column is not
+// constrained.
+func (query *Query) Range(column string) (Range, bool) {
+	var result Range
+	for _, predicate := range query.Find(column) {
+		val := predicate.Value()
+		if val == nil {
+			continue
+		}
+
+		switch predicate.Operator {
+		case node.GreaterThan:
+			result.Lower = tighter(result.Lower, &Bound{Value: val}, 1)
+		case node.GreaterOrEqualThan:
+			result.Lower = tighter(result.Lower, &Bound{Value: val, Inclusive: true}, 1)
+		case node.LessThan:
+			result.Upper = tighter(result.Upper, &Bound{Value: val}, -1)
+		case node.LessOrEqualThan:
+			result.Upper = tighter(result.Upper, &Bound{Value: val, Inclusive: true}, -1)
+		case node.Equal, node.IsNotDistinctFrom:
+			result.Lower = tighter(result.Lower, &Bound{Value: val, Inclusive: true}, 1)
+			result.Upper = tighter(result.Upper, &Bound{Value: val, Inclusive: true}, -1)
+		}
+	}
+
+	return result, result.Lower != nil || result.Upper != nil
+}
+
+// tighter returns the tightest of the given bounds. The direction is 1 for
+// lower bounds and -1 for upper bounds. The current bound is kept if the
+// bounds can not be compared.
+func tighter(current *Bound, bound *Bound, direction int) *Bound {
+	if current == nil {
+		return bound
+	}
+
+	result, err := value.Compare(bound.Value, current.Value)
+	if err != nil {
+		return current
+	}
+
+	switch result * direction {
+	case 1:
+		return bound
+	case 0:
+		if !bound.Inclusive {
+			return bound
+		}
+	}
+
+	return current
+}
@@ -0,0 +1,363 @@
+package planutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+func column(name string) *plan.Expression {
+	return &plan.Expression{Statement: &plan.Expression_Column{Column: &plan.Column{Name: name}}}
+}
+
+func constantOf(tb testing.TB, val any) *plan.Expression {
+	typ, buf, err := value.Encode(val, nil)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	return &plan.Expression{Statement: &plan.Expression_Constant{Constant: &plan.Constant{Type: typ, Value: buf}}}
+}
+
+func tuple(items ...*plan.Expression) *plan.Expression {
+	return &plan.Expression{Statement: &plan.Expression_Tuple{Tuple: &plan.Tuple{Expressions: items}}}
+}
+
+func expression(expr *plan.Expression) *plan.FilterExpression {
+	return &plan.FilterExpression{Condition: &plan.FilterExpression_Expression{Expression: expr}}
+}
+
+func compare(operator node.OperatorStatement, left *plan.Expression, right *plan.Expression) *plan.FilterExpression {
+	return &plan.FilterExpression{Condition: &plan.FilterExpression_ComparisonExpression{ComparisonExpression: &plan.ComparisonExpression{
+		Operator: operator,
+		Left:     expression(left),
+		Right:    expression(right),
+	}}}
+}
+
+func and(filters ...*plan.FilterExpression) *plan.FilterExpression {
+	result := filters[0]
+	for _, filter := range filters[1:] {
+		result = &plan.FilterExpression{Condition: &plan.FilterExpression_AndExpression{AndExpression: &plan.AndExpression{Left: result, Right: filter}}}
+	}
+
+	return result
+}
+
+func or(left *plan.FilterExpression, right *plan.FilterExpression) *plan.FilterExpression {
+	return &plan.FilterExpression{Condition: &plan.FilterExpression_OrExpression{OrExpression: &plan.OrExpression{Left: left, Right: right}}}
+}
+
+func parse(tb testing.TB, filter *plan.FilterExpression) *Query {
+	query, err := Parse(&plan.Literal{Filter: filter})
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	return query
+}
+
+func TestParse(t *testing.T) {
+	literal := &plan.Literal{
+		From:    &plan.From{Schema: "public", Table: "weather"},
+		Columns: []*plan.Expression{column("city"), constantOf(t, int64(1)), column("temperature")},
+		OrderBy: &plan.OrderBy{Expressions: []*plan.OrderExpression{
+			{Direction: 1, Expression: column("temperature")},
+			{Expression: column("city")},
+		}},
+	}
+
+	query, err := Parse(literal)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if query.Schema != "public" || query.Table != "weather" {
+		t.Errorf("unexpected table: %s.%s", query.Schema, query.Table)
+	}
+
+	if !reflect.DeepEqual(query.Columns, []string{"city", "", "temperature"}) {
+		t.Errorf("unexpected columns: %q", query.Columns)
+	}
+
+	if !query.Projected("city") || query.Projected("humidity") {
+		t.Error("unexpected projected columns")
+	}
+
+	if len(query.Order) != 2 || query.Order[0].Column != "temperature" || query.Order[0].Direction != Descending || query.Order[1].Direction != Ascending {
+		t.Errorf("unexpected order: %v", query.Order)
+	}
+}
+
+func TestParsePredicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *plan.FilterExpression
+		column   string
+		operator node.OperatorStatement
+		values   []any
+	}{
+		{
+			name:     "equal",
+			filter:   compare(node.Equal, column("city"), constantOf(t, "Amsterdam")),
+			column:   "city",
+			operator: node.Equal,
+			values:   []any{"Amsterdam"},
+		},
+		{
+			name:     "flipped",
+			filter:   compare(node.GreaterThan, constantOf(t, int64(10)), column("temperature")),
+			column:   "temperature",
+			operator: node.LessThan,
+			values:   []any{int64(10)},
+		},
+		{
+			name:     "in",
+			filter:   compare(node.In, column("city"), tuple(constantOf(t, "Amsterdam"), constantOf(t, "Utrecht"))),
+			column:   "city",
+			operator: node.In,
+			values:   []any{"Amsterdam", "Utrecht"},
+		},
+		{
+			name: "any",
+			filter: &plan.FilterExpression{Condition: &plan.FilterExpression_ComparisonExpression{ComparisonExpression: &plan.ComparisonExpression{
+				Operator:    node.Equal,
+				SubOperator: node.Any,
+				Left:        expression(column("city")),
+				Right:       expression(constantOf(t, []string{"Amsterdam", "Utrecht"})),
+			}}},
+			column:   "city",
+			operator: node.In,
+			values:   []any{"Amsterdam", "Utrecht"},
+		},
+		{
+			name:     "boolean column",
+			filter:   expression(column("active")),
+			column:   "active",
+			operator: node.Equal,
+			values:   []any{true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := parse(t, test.filter)
+			if len(query.Predicates) != 1 || len(query.Residual) != 0 {
+				t.Fatalf("unexpected predicates: %v, residual: %v", query.Predicates, query.Residual)
+			}
+
+			predicate := query.Predicates[0]
+			if predicate.Column != test.column || predicate.Operator != test.operator || !reflect.DeepEqual(predicate.Values, test.values) {
+				t.Errorf("unexpected predicate: %s %s %v", predicate.Column, predicate.Operator, predicate.Values)
+			}
+
+			if predicate.Expression != test.filter {
+				t.Error("expected the predicate to reference its filter expression")
+			}
+		})
+	}
+}
+
+func TestParseResidual(t *testing.T) {
+	either := or(
+		compare(node.Equal, column("city"), constantOf(t, "Amsterdam")),
+		compare(node.Equal, column("city"), constantOf(t, "Utrecht")),
+	)
+
+	columns := compare(node.Equal, column("city"), column("country"))
+	function := compare(node.Equal, &plan.Expression{Statement: &plan.Expression_Function{Function: &plan.Function{Name: "lower", Expressions: []*plan.Expression{column("city")}}}}, constantOf(t, "amsterdam"))
+	temperature := compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10)))
+
+	query := parse(t, and(either, temperature, columns, function))
+	if len(query.Predicates) != 1 || query.Predicates[0].Column != "temperature" {
+		t.Errorf("unexpected predicates: %v", query.Predicates)
+	}
+
+	expected := []*plan.FilterExpression{either, columns, function}
+	if len(query.Residual) != len(expected) {
+		t.Fatalf("unexpected residual: %v", query.Residual)
+	}
+
+	for index := range expected {
+		if query.Residual[index] != expected[index] {
+			t.Errorf("unexpected residual %d: %v", index, query.Residual[index])
+		}
+	}
+}
+
+func TestParseUndecodableConstant(t *testing.T) {
+	invalid := &plan.Expression{Statement: &plan.Expression_Constant{Constant: &plan.Constant{Type: constantOf(t, int64(1)).GetConstant().Type, Value: []byte{1}}}}
+
+	_, err := Parse(&plan.Literal{Filter: compare(node.Equal, column("temperature"), invalid)})
+	if err == nil {
+		t.Error("expected an undecodable constant to be rejected")
+	}
+}
+
+func TestFlip(t *testing.T) {
+	tests := []struct {
+		operator node.OperatorStatement
+		expected node.OperatorStatement
+		ok       bool
+	}{
+		{operator: node.Equal, expected: node.Equal, ok: true},
+		{operator: node.NotEqual, expected: node.NotEqual, ok: true},
+		{operator: node.GreaterThan, expected: node.LessThan, ok: true},
+		{operator: node.GreaterOrEqualThan, expected: node.LessOrEqualThan, ok: true},
+		{operator: node.LessThan, expected: node.GreaterThan, ok: true},
+		{operator: node.LessOrEqualThan, expected: node.GreaterOrEqualThan, ok: true},
+		{operator: node.IsDistinctFrom, expected: node.IsDistinctFrom, ok: true},
+		{operator: node.IsNotDistinctFrom, expected: node.IsNotDistinctFrom, ok: true},
+		{operator: node.Like},
+		{operator: node.In},
+	}
+
+	for _, test := range tests {
+		flipped, ok := Flip(test.operator)
+		if ok != test.ok || (ok && flipped != test.expected) {
+			t.Errorf("%s: unexpected flip %s, %t", test.operator, flipped, ok)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   *plan.FilterExpression
+		expected []any
+		ok       bool
+	}{
+		{
+			name:     "equal",
+			filter:   compare(node.Equal, column("city"), constantOf(t, "Amsterdam")),
+			expected: []any{"Amsterdam"},
+			ok:       true,
+		},
+		{
+			name:     "not distinct",
+			filter:   compare(node.IsNotDistinctFrom, column("city"), constantOf(t, "Amsterdam")),
+			expected: []any{"Amsterdam"},
+			ok:       true,
+		},
+		{
+			name:     "in",
+			filter:   compare(node.In, column("city"), tuple(constantOf(t, "Amsterdam"), constantOf(t, "Utrecht"))),
+			expected: []any{"Amsterdam", "Utrecht"},
+			ok:       true,
+		},
+		{
+			name: "first",
+			filter: and(
+				compare(node.Like, column("city"), constantOf(t, "A%")),
+				compare(node.Equal, column("city"), constantOf(t, "Amsterdam")),
+				compare(node.Equal, column("city"), constantOf(t, "Utrecht")),
+			),
+			expected: []any{"Amsterdam"},
+			ok:       true,
+		},
+		{
+			name:   "not equal",
+			filter: compare(node.NotEqual, column("city"), constantOf(t, "Amsterdam")),
+		},
+		{
+			name:   "other column",
+			filter: compare(node.Equal, column("country"), constantOf(t, "NL")),
+		},
+		{
+			name: "or",
+			filter: or(
+				compare(node.Equal, column("city"), constantOf(t, "Amsterdam")),
+				compare(node.Equal, column("city"), constantOf(t, "Utrecht")),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, ok := parse(t, test.filter).Equal("city")
+			if ok != test.ok || !reflect.DeepEqual(values, test.expected) {
+				t.Errorf("unexpected values: %v, %t", values, ok)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	bound := func(val any, inclusive bool) *Bound {
+		return &Bound{Value: val, Inclusive: inclusive}
+	}
+
+	tests := []struct {
+		name     string
+		filter   *plan.FilterExpression
+		expected Range
+		ok       bool
+	}{
+		{
+			name:     "lower",
+			filter:   compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10))),
+			expected: Range{Lower: bound(int64(10), false)},
+			ok:       true,
+		},
+		{
+			name:     "flipped upper",
+			filter:   compare(node.GreaterOrEqualThan, constantOf(t, int64(20)), column("temperature")),
+			expected: Range{Upper: bound(int64(20), true)},
+			ok:       true,
+		},
+		{
+			name: "tightest",
+			filter: and(
+				compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10))),
+				compare(node.GreaterOrEqualThan, column("temperature"), constantOf(t, int64(15))),
+				compare(node.LessThan, column("temperature"), constantOf(t, int64(30))),
+				compare(node.LessOrEqualThan, column("temperature"), constantOf(t, int64(25))),
+			),
+			expected: Range{Lower: bound(int64(15), true), Upper: bound(int64(25), true)},
+			ok:       true,
+		},
+		{
+			name: "exclusive over inclusive",
+			filter: and(
+				compare(node.GreaterOrEqualThan, column("temperature"), constantOf(t, int64(10))),
+				compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10))),
+			),
+			expected: Range{Lower: bound(int64(10), false)},
+			ok:       true,
+		},
+		{
+			name: "equal",
+			filter: and(
+				compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10))),
+				compare(node.Equal, column("temperature"), constantOf(t, int64(12))),
+			),
+			expected: Range{Lower: bound(int64(12), true), Upper: bound(int64(12), true)},
+			ok:       true,
+		},
+		{
+			name: "incomparable",
+			filter: and(
+				compare(node.GreaterThan, column("temperature"), constantOf(t, int64(10))),
+				compare(node.GreaterThan, column("temperature"), constantOf(t, "warm")),
+			),
+			expected: Range{Lower: bound(int64(10), false)},
+			ok:       true,
+		},
+		{
+			name:   "unconstrained",
+			filter: compare(node.NotEqual, column("temperature"), constantOf(t, int64(10))),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, ok := parse(t, test.filter).Range("temperature")
+			if ok != test.ok || !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("unexpected range: %+v, %+v, %t", result.Lower, result.Upper, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package lunodbgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// ReconnectPolicy defines how the Connector reconnects to Stargate once the
+// connection has been closed. The backoff between attempts grows
+// exponentially from InitialBackoff up to MaxBackoff.
+type ReconnectPolicy struct {
+	// InitialBackoff is the backoff before the first reconnect attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound of the backoff between attempts.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the backoff grows after every attempt.
+	Multiplier float64
+	// Jitter randomizes the backoff by the given fraction (0-1) to prevent
+	// connectors from reconnecting in lockstep.
+	Jitter float64
+	// MaxAttempts is the maximum number of consecutive reconnect attempts. Zero
+	// retries forever.
+	MaxAttempts int
+	// ResetAfter resets the number of attempts once a connection has been
+	// stable for the given duration. Zero never resets the attempts.
+	ResetAfter time.Duration
+}
+
+// DefaultReconnectPolicy represents the reconnect policy used when no policy
+// has been configured.
+var DefaultReconnectPolicy = ReconnectPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	ResetAfter:     time.Minute,
+}
+
+// Validate returns an error if the reconnect policy is invalid.
+func (policy ReconnectPolicy) Validate() error {
+	if policy.InitialBackoff <= 0 {
+		return fmt.Errorf("invalid initial backoff: %s", policy.InitialBackoff)
+	}
+
+	if policy.MaxBackoff < policy.InitialBackoff {
+		return fmt.Errorf("max backoff %s is lower than the initial backoff %s", policy.MaxBackoff, policy.InitialBackoff)
+	}
+
+	if policy.Multiplier < 1 {
+		return fmt.Errorf("invalid backoff multiplier: %v", policy.Multiplier)
+	}
+
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		return fmt.Errorf("invalid backoff jitter: %v", policy.Jitter)
+	}
+
+	if policy.MaxAttempts < 0 {
+		return fmt.Errorf("invalid max attempts: %d", policy.MaxAttempts)
+	}
+
+	if policy.ResetAfter < 0 {
+		return fmt.Errorf("invalid reset after duration: %s", policy.ResetAfter)
+	}
+
+	return nil
+}
+
+// Backoff returns the backoff before the given reconnect attempt, starting
+// at one.
+func (policy ReconnectPolicy) Backoff(attempt int) time.Duration {
+	backoff := float64(policy.InitialBackoff) * math.Pow(policy.Multiplier, float64(max(attempt-1, 0)))
+	backoff = min(backoff, float64(policy.MaxBackoff))
+
+	if policy.Jitter > 0 {
+		backoff += backoff * policy.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(min(backoff, float64(policy.MaxBackoff)))
+}
+
+// ErrReconnectAttemptsExhausted is wrapped by ReconnectError once the
+// reconnect policy gave up reconnecting to Stargate.
+var ErrReconnectAttemptsExhausted = errors.New("reconnect attempts exhausted")
+
+// ReconnectError is returned by Connector.Serve once the configured reconnect
+// policy gave up reconnecting to Stargate.
+type ReconnectError struct {
+	// Attempts is the number of consecutive reconnect attempts made.
+	Attempts int
+	// Err is the error of the last connection attempt, if any.
+	Err error
+}
+
+func (err *ReconnectError) Error() string {
+	if err.Err == nil {
+		return fmt.Sprintf("%s after %d attempts", ErrReconnectAttemptsExhausted, err.Attempts)
+	}
+
+	return fmt.Sprintf("%s after %d attempts: %s", ErrReconnectAttemptsExhausted, err.Attempts, err.Err)
+}
+
+func (err *ReconnectError) Unwrap() []error {
+	return []error{ErrReconnectAttemptsExhausted, err.Err}
+}
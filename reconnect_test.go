@@ -0,0 +1,121 @@
+package lunodbgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicyBackoff(t *testing.T) {
+	policy := ReconnectPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{attempt: 0, expected: 100 * time.Millisecond},
+		{attempt: 1, expected: 100 * time.Millisecond},
+		{attempt: 2, expected: 200 * time.Millisecond},
+		{attempt: 3, expected: 400 * time.Millisecond},
+		{attempt: 4, expected: 800 * time.Millisecond},
+		{attempt: 5, expected: time.Second},
+		{attempt: 100, expected: time.Second},
+	}
+
+	for _, test := range tests {
+		backoff := policy.Backoff(test.attempt)
+		if backoff != test.expected {
+			t.Errorf("attempt %d: unexpected backoff %s, expected %s", test.attempt, backoff, test.expected)
+		}
+	}
+}
+
+func TestReconnectPolicyBackoffJitter(t *testing.T) {
+	policy := ReconnectPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		backoff := policy.Backoff(2)
+		if backoff < 100*time.Millisecond || backoff > 300*time.Millisecond {
+			t.Fatalf("backoff %s out of jitter bounds", backoff)
+		}
+
+		seen[backoff] = true
+
+		backoff = policy.Backoff(10)
+		if backoff < 500*time.Millisecond || backoff > time.Second {
+			t.Fatalf("backoff %s out of jitter bounds", backoff)
+		}
+	}
+
+	if len(seen) < 2 {
+		t.Error("expected the backoff to be randomized")
+	}
+}
+
+func TestReconnectPolicyValidate(t *testing.T) {
+	valid := DefaultReconnectPolicy
+
+	tests := map[string]func(policy *ReconnectPolicy){
+		"initial backoff": func(policy *ReconnectPolicy) { policy.InitialBackoff = 0 },
+		"max backoff":     func(policy *ReconnectPolicy) { policy.MaxBackoff = policy.InitialBackoff - 1 },
+		"multiplier":      func(policy *ReconnectPolicy) { policy.Multiplier = 0.5 },
+		"negative jitter": func(policy *ReconnectPolicy) { policy.Jitter = -0.1 },
+		"jitter":          func(policy *ReconnectPolicy) { policy.Jitter = 1.1 },
+		"max attempts":    func(policy *ReconnectPolicy) { policy.MaxAttempts = -1 },
+		"reset after":     func(policy *ReconnectPolicy) { policy.ResetAfter = -time.Second },
+	}
+
+	err := valid.Validate()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := valid
+			mutate(&policy)
+
+			err := policy.Validate()
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReconnectError(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	var err error = &ReconnectError{Attempts: 3, Err: cause}
+	if !errors.Is(err, ErrReconnectAttemptsExhausted) {
+		t.Error("expected the error to wrap ErrReconnectAttemptsExhausted")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Error("expected the error to wrap the last connection error")
+	}
+
+	var target *ReconnectError
+	if !errors.As(err, &target) || target.Attempts != 3 {
+		t.Errorf("unexpected reconnect error: %v", target)
+	}
+
+	err = &ReconnectError{Attempts: 1}
+	if !errors.Is(err, ErrReconnectAttemptsExhausted) {
+		t.Error("expected the error to wrap ErrReconnectAttemptsExhausted")
+	}
+
+	if err.Error() != "reconnect attempts exhausted after 1 attempts" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
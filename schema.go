@@ -1,6 +1,8 @@
 package lunodbgo
 
 import (
+	"fmt"
+
 	nodepb "github.com/cloudproud/lunodb.api/proto/node"
 	typespb "github.com/cloudproud/lunodb.api/proto/types"
 )
@@ -37,6 +39,24 @@ func (tables Tables) Proto() []*nodepb.Table {
 	return result
 }
 
+// Find returns the table with the given name. The schema is ignored when
+// empty.
+func (tables Tables) Find(schema string, name string) (Table, bool) {
+	for _, table := range tables {
+		if table.Name != name {
+			continue
+		}
+
+		if schema != "" && table.Schema != schema {
+			continue
+		}
+
+		return table, true
+	}
+
+	return Table{}, false
+}
+
 type Table struct {
 	Name       string
 	Schema     string
@@ -73,6 +93,17 @@ func (columns Columns) Proto() []*nodepb.Column {
 	return result
 }
 
+// Find returns the column with the given name.
+func (columns Columns) Find(name string) (Column, bool) {
+	for _, column := range columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
 type Column struct {
 	Name      string
 	Type      *typespb.Type
@@ -119,6 +150,18 @@ const (
 	VariableVariable ComparisonType = 2
 )
 
+// String returns a human readable name of the comparison type.
+func (typ ComparisonType) String() string {
+	switch typ {
+	case VariableConstant:
+		return "constant"
+	case VariableVariable:
+		return "column"
+	default:
+		return fmt.Sprintf("ComparisonType(%d)", int32(typ))
+	}
+}
+
 type Statement int32
 
 const (
@@ -152,3 +195,50 @@ const (
 	StatementAll                Statement = 28
 	StatementBinary             Statement = 29
 )
+
+var statementNames = map[Statement]string{
+	StatementWhere:              "WHERE",
+	StatementLimit:              "LIMIT",
+	StatementOffset:             "OFFSET",
+	StatementOrder:              "ORDER BY",
+	StatementLeftJoin:           "LEFT JOIN",
+	StatementRightJoin:          "RIGHT JOIN",
+	StatementInnerJoin:          "INNER JOIN",
+	StatementOuterJoin:          "OUTER JOIN",
+	StatementEqual:              "=",
+	StatementNotEqual:           "<>",
+	StatementIn:                 "IN",
+	StatementNotIn:              "NOT IN",
+	StatementGreaterThan:        ">",
+	StatementGreaterOrEqualThan: ">=",
+	StatementLessThan:           "<",
+	StatementLessOrEqualThan:    "<=",
+	StatementLike:               "LIKE",
+	StatementNotLike:            "NOT LIKE",
+	StatementILike:              "ILIKE",
+	StatementNotILike:           "NOT ILIKE",
+	StatementRegMatch:           "~",
+	StatementNotRegMatch:        "!~",
+	StatementRegIMatch:          "~*",
+	StatementNotRegIMatch:       "!~*",
+	StatementIsDistinctFrom:     "IS DISTINCT FROM",
+	StatementIsNotDistinctFrom:  "IS NOT DISTINCT FROM",
+	StatementAny:                "ANY",
+	StatementAll:                "ALL",
+	StatementBinary:             "binary",
+}
+
+// String returns the SQL representation of the statement (e.g. >=).
+func (statement Statement) String() string {
+	name, ok := statementNames[statement]
+	if !ok {
+		return fmt.Sprintf("Statement(%d)", int32(statement))
+	}
+
+	return name
+}
+
+// IsComparison returns true if the statement compares two values.
+func (statement Statement) IsComparison() bool {
+	return statement >= StatementEqual && statement <= StatementIsNotDistinctFrom
+}
@@ -0,0 +1,145 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"google.golang.org/grpc"
+)
+
+// DefaultSendQueueSize represents the default number of outbound message
+// batches that may be queued before callers writing to Stargate are blocked.
+const DefaultSendQueueSize = 64
+
+// ErrSenderClosed is returned when attempting to send a message over a stream
+// which is no longer being written to.
+var ErrSenderClosed = errors.New("sender closed")
+
+// stream represents the bidirectional Stargate connector stream.
+type stream = grpc.BidiStreamingClient[lunopb.ConnectorResponse, lunopb.ConnectorRequest]
+
+// sender serializes all outbound messages written to a single stream. gRPC
+// does not allow concurrent calls to Send on the same stream, messages are
+// therefore queued and written by a single goroutine. Callers are blocked once
+// the queue is full, propagating backpressure to the writers.
+type sender struct {
+	stream  stream
+	queue   chan []*lunopb.ConnectorResponse
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	done    chan struct{}
+	err     error
+}
+
+func newSender(stream stream, size int) *sender {
+	return &sender{
+		stream:  stream,
+		queue:   make(chan []*lunopb.ConnectorResponse, size),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// run drains the send queue until the given context is cancelled, the sender
+// is closed or an error occurs while writing to the stream.
+func (sender *sender) run(ctx context.Context) error {
+	defer close(sender.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			sender.err = ErrSenderClosed
+			return nil
+		case <-sender.closing:
+			return sender.flush()
+		case msgs := <-sender.queue:
+			err := sender.send(msgs)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// flush writes all remaining queued messages and closes the sending side of
+// the stream.
+func (sender *sender) flush() error {
+	sender.err = ErrSenderClosed
+
+	for {
+		select {
+		case msgs := <-sender.queue:
+			err := sender.send(msgs)
+			if err != nil {
+				return err
+			}
+		default:
+			return sender.stream.CloseSend()
+		}
+	}
+}
+
+func (sender *sender) send(msgs []*lunopb.ConnectorResponse) error {
+	for _, msg := range msgs {
+		err := sender.stream.Send(msg)
+		if err != nil {
+			sender.err = err
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close stops accepting new messages and blocks until all queued messages
+// have been written or the given context is done.
+func (sender *sender) close(ctx context.Context) error {
+	sender.mu.Lock()
+	if !sender.closed {
+		sender.closed = true
+		close(sender.closing)
+	}
+	sender.mu.Unlock()
+
+	select {
+	case <-sender.done:
+		if sender.err == ErrSenderClosed {
+			return nil
+		}
+
+		return sender.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send queues the given messages to be written to the stream in order. The
+// messages are never interleaved with messages queued by other callers. Send
+// blocks until the messages are queued, the sender stops or the context is
+// cancelled.
+func (sender *sender) Send(ctx context.Context, msgs ...*lunopb.ConnectorResponse) error {
+	sender.mu.RLock()
+	defer sender.mu.RUnlock()
+
+	if sender.closed {
+		return ErrSenderClosed
+	}
+
+	select {
+	case <-sender.done:
+		return sender.err
+	default:
+	}
+
+	select {
+	case sender.queue <- msgs:
+		return nil
+	case <-sender.done:
+		return sender.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
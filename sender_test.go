@@ -0,0 +1,197 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"google.golang.org/grpc"
+)
+
+// testStream is an in-memory connector stream. Requests are received from the
+// requests channel until it is closed, sent responses are recorded. Send
+// blocks while the stream is paused.
+type testStream struct {
+	grpc.ClientStream
+	ctx      context.Context
+	requests chan *lunopb.ConnectorRequest
+	paused   chan struct{}
+	closed   chan struct{}
+
+	mu        sync.Mutex
+	responses []*lunopb.ConnectorResponse
+	close     sync.Once
+}
+
+func newTestStream(ctx context.Context) *testStream {
+	paused := make(chan struct{})
+	close(paused)
+
+	return &testStream{
+		ctx:      ctx,
+		requests: make(chan *lunopb.ConnectorRequest),
+		paused:   paused,
+		closed:   make(chan struct{}),
+	}
+}
+
+func (stream *testStream) Send(msg *lunopb.ConnectorResponse) error {
+	<-stream.paused
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	stream.responses = append(stream.responses, msg)
+	return nil
+}
+
+func (stream *testStream) Recv() (*lunopb.ConnectorRequest, error) {
+	select {
+	case msg, ok := <-stream.requests:
+		if !ok {
+			return nil, io.EOF
+		}
+
+		return msg, nil
+	case <-stream.ctx.Done():
+		return nil, stream.ctx.Err()
+	}
+}
+
+func (stream *testStream) CloseSend() error {
+	stream.close.Do(func() {
+		close(stream.closed)
+	})
+
+	return nil
+}
+
+// sent returns the responses sent over the stream.
+func (stream *testStream) sent() []*lunopb.ConnectorResponse {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	return append([]*lunopb.ConnectorResponse(nil), stream.responses...)
+}
+
+func TestSenderOrdering(t *testing.T) {
+	ctx := context.Background()
+	stream := newTestStream(ctx)
+	sender := newSender(stream, 4)
+	go sender.run(ctx) //nolint:errcheck
+
+	const writers = 8
+	const batches = 50
+
+	var wg sync.WaitGroup
+	for writer := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for batch := range batches {
+				id := uint32(writer*batches + batch)
+				err := sender.Send(ctx, &lunopb.ConnectorResponse{Id: id}, &lunopb.ConnectorResponse{Id: id})
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	err := sender.close(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sent := stream.sent()
+	if len(sent) != writers*batches*2 {
+		t.Fatalf("unexpected number of messages: %d", len(sent))
+	}
+
+	last := make(map[uint32]int)
+	for index := 0; index < len(sent); index += 2 {
+		if sent[index].Id != sent[index+1].Id {
+			t.Fatalf("batch %d interleaved with batch %d", sent[index].Id, sent[index+1].Id)
+		}
+
+		writer := int(sent[index].Id) / batches
+		batch := int(sent[index].Id) % batches
+		if previous, ok := last[uint32(writer)]; ok && batch <= previous {
+			t.Fatalf("writer %d: batch %d sent after batch %d", writer, batch, previous)
+		}
+
+		last[uint32(writer)] = batch
+	}
+
+	select {
+	case <-stream.closed:
+	default:
+		t.Error("expected the sending side of the stream to be closed")
+	}
+}
+
+func TestSenderBackpressure(t *testing.T) {
+	ctx := context.Background()
+	stream := newTestStream(ctx)
+	stream.paused = make(chan struct{})
+
+	sender := newSender(stream, 1)
+	go sender.run(ctx) //nolint:errcheck
+
+	// NOTE: the first message is taken by the paused run loop, the second
+	// fills the queue.
+	for range 2 {
+		err := sender.Send(ctx, &lunopb.ConnectorResponse{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	blocked, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+
+	err := sender.Send(blocked, &lunopb.ConnectorResponse{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	close(stream.paused)
+
+	err = sender.Send(ctx, &lunopb.ConnectorResponse{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = sender.close(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(stream.sent()) != 3 {
+		t.Errorf("unexpected number of messages: %d", len(stream.sent()))
+	}
+}
+
+func TestSenderClosed(t *testing.T) {
+	ctx := context.Background()
+	sender := newSender(newTestStream(ctx), 1)
+	go sender.run(ctx) //nolint:errcheck
+
+	err := sender.close(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = sender.Send(ctx, &lunopb.ConnectorResponse{})
+	if !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
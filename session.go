@@ -0,0 +1,88 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrShuttingDown is returned to Stargate for statements received while the
+// Connector is shutting down.
+var ErrShuttingDown = errors.New("connector is shutting down")
+
+// session represents a single established Stargate stream and all requests
+// which are in-flight on it.
+type session struct {
+	stream     stream
+	sender     *sender
+	statements *statements
+	cancel     context.CancelFunc
+	// done is closed once the receive loop has returned.
+	done chan struct{}
+}
+
+// drainGracePeriod is the duration statements cancelled during a shutdown are
+// given to return and flush their results, and the duration Stargate is given
+// to close the stream once all messages have been flushed.
+var drainGracePeriod = 5 * time.Second
+
+// drain stops accepting new statements and waits for all in-flight statements
+// to complete. Statements which are still running once the context is done
+// are cancelled and given a grace period to report their result. All pending
+// messages are flushed before the stream is closed, the stream context is
+// cancelled once Stargate closed the stream or the grace period expired.
+func (session *session) drain(ctx context.Context) error {
+	defer session.cancel()
+
+	err := session.statements.drain(ctx)
+	if err != nil {
+		session.statements.cancelAll(ErrShuttingDown)
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), drainGracePeriod)
+		defer cancel()
+
+		session.statements.drain(ctx) //nolint:errcheck
+	}
+
+	err = errors.Join(err, session.sender.close(ctx))
+
+	// NOTE: gRPC only guarantees the delivery of sent messages once the
+	// receiving side of the stream has been closed.
+	timer := time.NewTimer(drainGracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-session.done:
+		return err
+	case <-timer.C:
+		return err
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+}
+
+// Shutdown gracefully shuts down the Connector. New statements are rejected
+// while in-flight statements are allowed to complete until the given context
+// is done, after which they are cancelled and given a short grace period to
+// report their result. Pending rows and end of execution messages are flushed
+// before the stream is closed. Serve returns once the
+// Connector has been shut down.
+func (connector *Connector) Shutdown(ctx context.Context) error {
+	connector.mu.Lock()
+	if !connector.stopped {
+		connector.stopped = true
+		close(connector.shutdown)
+	}
+
+	connector.healthy = false
+	session := connector.session
+	connector.mu.Unlock()
+
+	if session == nil {
+		return nil
+	}
+
+	connector.logger.Info("shutting down connector, draining in-flight statements")
+	return session.drain(ctx)
+}
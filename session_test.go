@@ -0,0 +1,83 @@
+package lunodbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"go.uber.org/zap"
+)
+
+func TestShutdownUnclosedStream(t *testing.T) {
+	grace := drainGracePeriod
+	drainGracePeriod = 50 * time.Millisecond
+	t.Cleanup(func() {
+		drainGracePeriod = grace
+	})
+
+	connector, err := NewConnector(WithLogger(zap.NewNop()))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// NOTE: the requests are never closed, the server never closes its side
+	// of the stream.
+	stream := newTestStream(ctx)
+	session := &session{
+		stream:     stream,
+		sender:     newSender(stream, DefaultSendQueueSize),
+		statements: newStatements(0),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	started := make(chan struct{})
+	handler := &testHandler{
+		tables: testTables,
+		scan: func(ctx context.Context, _ *plan.Literal, writer Writer) error {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			return writer.Write(ctx, []any{"Amsterdam"})
+		},
+	}
+
+	go connector.recvLoop(ctx, session, handler) //nolint:errcheck
+
+	stream.requests <- executeRequest(1)
+	<-started
+
+	result := make(chan error, 1)
+	go func() {
+		result <- connector.Shutdown(context.Background())
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return while the server never closed the stream")
+	}
+
+	sent := stream.sent()
+	if len(sent) != 2 {
+		t.Fatalf("unexpected number of messages: %d", len(sent))
+	}
+
+	_, ok := sent[1].GetExecuteStatement().GetResult().(*lunopb.ExecuteStatementResponse_EOE)
+	if !ok {
+		t.Errorf("unexpected result: %v", sent[1])
+	}
+
+	select {
+	case <-stream.closed:
+	default:
+		t.Error("expected the sending side of the stream to be closed")
+	}
+}
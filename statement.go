@@ -0,0 +1,105 @@
+package lunodbgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrStatementTimeout is the cause of a statement context being cancelled
+// once the configured statement timeout expired.
+var ErrStatementTimeout = errors.New("statement timeout exceeded")
+
+// statements keeps track of all in-flight statements within a single
+// session. Every statement receives its own context derived from the session
+// context, allowing individual statements to be cancelled without affecting
+// others.
+//
+// NOTE: statements are tracked by a sequence rather than their request id,
+// request ids could be reused by Stargate while a statement is in-flight. The
+// API does not carry a cancel message yet, statements are only cancelled once
+// their timeout expires or the Connector shuts down.
+type statements struct {
+	timeout time.Duration
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	cancels map[uint64]context.CancelCauseFunc
+}
+
+func newStatements(timeout time.Duration) *statements {
+	return &statements{
+		timeout: timeout,
+		cancels: make(map[uint64]context.CancelCauseFunc),
+	}
+}
+
+// start constructs a new statement context. The returned function has to be
+// called once the statement has been completed. ErrShuttingDown is returned
+// once the statements are being drained.
+func (statements *statements) start(ctx context.Context) (context.Context, func(), error) {
+	statements.mu.Lock()
+	defer statements.mu.Unlock()
+
+	if statements.closed {
+		return nil, nil, ErrShuttingDown
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	stop := func() {}
+	if statements.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, statements.timeout, ErrStatementTimeout)
+		stop = cancelTimeout
+	}
+
+	id := statements.next
+	statements.next++
+	statements.cancels[id] = cancel
+	statements.wg.Add(1)
+
+	return ctx, func() {
+		statements.mu.Lock()
+		delete(statements.cancels, id)
+		statements.mu.Unlock()
+
+		stop()
+		cancel(nil)
+		statements.wg.Done()
+	}, nil
+}
+
+// cancelAll cancels all in-flight statements with the given cause.
+func (statements *statements) cancelAll(cause error) {
+	statements.mu.Lock()
+	defer statements.mu.Unlock()
+
+	for id, cancel := range statements.cancels {
+		cancel(cause)
+		delete(statements.cancels, id)
+	}
+}
+
+// drain stops accepting new statements and blocks until all in-flight
+// statements have been completed or the given context is done.
+func (statements *statements) drain(ctx context.Context) error {
+	statements.mu.Lock()
+	statements.closed = true
+	statements.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		statements.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,134 @@
+package lunodbgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+// structPlans caches the field plans of struct types.
+var structPlans sync.Map
+
+// structPlan represents the fields of a struct type mapped onto columns.
+type structPlan struct {
+	fields []value.StructField
+	lookup map[string]int
+}
+
+// fieldsOf returns the (cached) field plan of the given struct type. The
+// fields are resolved using value.StructFields.
+func fieldsOf(typ reflect.Type) *structPlan {
+	cached, ok := structPlans.Load(typ)
+	if ok {
+		return cached.(*structPlan)
+	}
+
+	plan := &structPlan{
+		fields: value.StructFields(typ),
+		lookup: make(map[string]int),
+	}
+
+	for index, field := range plan.fields {
+		plan.lookup[field.Name] = index
+	}
+
+	cached, _ = structPlans.LoadOrStore(typ, plan)
+	return cached.(*structPlan)
+}
+
+// WriteStruct writes the given struct, or pointer to a struct, as a single
+// row. Struct fields are reordered to match the projected columns when the
+// writer implements ColumnWriter, otherwise all fields are written in their
+// declaration order.
+func WriteStruct(ctx context.Context, writer Writer, val any) error {
+	rv := reflect.Indirect(reflect.ValueOf(val))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("unable to write %T as struct", val)
+	}
+
+	plan := fieldsOf(rv.Type())
+	mapping, err := plan.mapping(rv.Type(), Projection(writer))
+	if err != nil {
+		return err
+	}
+
+	return writer.Write(ctx, plan.values(rv, mapping))
+}
+
+// StructWriter writes values of type T as rows. The mapping between the
+// struct fields and the projected columns is resolved once.
+type StructWriter[T any] struct {
+	writer  Writer
+	plan    *structPlan
+	mapping []int
+	err     error
+}
+
+// NewStructWriter constructs a new StructWriter writing rows of type T to the
+// given writer.
+func NewStructWriter[T any](writer Writer) *StructWriter[T] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return &StructWriter[T]{err: fmt.Errorf("unable to write %s as struct", typ)}
+	}
+
+	plan := fieldsOf(typ)
+	mapping, err := plan.mapping(typ, Projection(writer))
+	return &StructWriter[T]{
+		writer:  writer,
+		plan:    plan,
+		mapping: mapping,
+		err:     err,
+	}
+}
+
+// Write writes the given value as a single row. An error is returned if the
+// given value is nil.
+func (writer *StructWriter[T]) Write(ctx context.Context, val *T) error {
+	if writer.err != nil {
+		return writer.err
+	}
+
+	if val == nil {
+		return fmt.Errorf("unable to write nil %T as struct", val)
+	}
+
+	return writer.writer.Write(ctx, writer.plan.values(reflect.ValueOf(val).Elem(), writer.mapping))
+}
+
+// mapping returns the field indexes of the given columns. All fields are
+// returned in their declaration order if no columns are given.
+func (plan *structPlan) mapping(typ reflect.Type, columns []string) ([]int, error) {
+	if columns == nil {
+		mapping := make([]int, len(plan.fields))
+		for index := range mapping {
+			mapping[index] = index
+		}
+
+		return mapping, nil
+	}
+
+	mapping := make([]int, len(columns))
+	for index, column := range columns {
+		field, ok := plan.lookup[column]
+		if !ok {
+			return nil, fmt.Errorf("column %q has no matching field in %s", column, typ)
+		}
+
+		mapping[index] = field
+	}
+
+	return mapping, nil
+}
+
+func (plan *structPlan) values(rv reflect.Value, mapping []int) []any {
+	values := make([]any, len(mapping))
+	for index, field := range mapping {
+		values[index] = rv.FieldByIndex(plan.fields[field].Index).Interface()
+	}
+
+	return values
+}
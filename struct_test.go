@@ -0,0 +1,41 @@
+package lunodbgo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type weather struct {
+	City        string  `lunodb:"city"`
+	Temperature int64   `lunodb:"temperature"`
+	Humidity    float64 `lunodb:"humidity"`
+}
+
+func TestStructWriter(t *testing.T) {
+	var rows [][]any
+	writer := NewStructWriter[weather](WriterFunc(func(_ context.Context, values []any) error {
+		rows = append(rows, values)
+		return nil
+	}))
+
+	err := writer.Write(context.Background(), &weather{City: "Amsterdam", Temperature: 12, Humidity: 80.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := [][]any{{"Amsterdam", int64(12), 80.5}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+
+	err = writer.Write(context.Background(), nil)
+	if err == nil {
+		t.Error("expected an error writing a nil struct")
+	}
+
+	err = WriteStruct(context.Background(), writer.writer, (*weather)(nil))
+	if err == nil {
+		t.Error("expected an error writing a nil struct")
+	}
+}
@@ -0,0 +1,115 @@
+package lunodbgo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudproud/lunodb.go/value"
+)
+
+// operatorNames maps the operator names accepted within struct tags onto
+// their statements.
+var operatorNames = map[string]Statement{
+	"eq":           StatementEqual,
+	"neq":          StatementNotEqual,
+	"in":           StatementIn,
+	"notin":        StatementNotIn,
+	"gt":           StatementGreaterThan,
+	"gte":          StatementGreaterOrEqualThan,
+	"lt":           StatementLessThan,
+	"lte":          StatementLessOrEqualThan,
+	"like":         StatementLike,
+	"notlike":      StatementNotLike,
+	"ilike":        StatementILike,
+	"notilike":     StatementNotILike,
+	"regmatch":     StatementRegMatch,
+	"notregmatch":  StatementNotRegMatch,
+	"regimatch":    StatementRegIMatch,
+	"notregimatch": StatementNotRegIMatch,
+	"distinct":     StatementIsDistinctFrom,
+	"notdistinct":  StatementIsNotDistinctFrom,
+}
+
+// TableOf derives a table from the exported fields of the struct type T. The
+// columns are named after the `lunodb:"name"` tag, falling back to the field
+// name, and typed using the same type mapping as value.Encode. Pointer fields
+// and database/sql nullable fields, such as sql.NullString, are nullable and
+// typed after the value they hold. Additional tag options configure the column:
+//
+//	required          the column has to be constrained within the query
+//	indexed           the column is indexed
+//	nullable          the column is nullable
+//	operators=eq|in   the operators supported on the column
+//
+// Operators are constrained against constants. Supported operator names are
+// eq, neq, in, notin, gt, gte, lt, lte, like, notlike, ilike, notilike,
+// regmatch, notregmatch, regimatch, notregimatch, distinct and notdistinct.
+func TableOf[T any](name string, schema string, catalog string) (Table, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return Table{}, fmt.Errorf("unable to derive table from %s: not a struct", typ)
+	}
+
+	plan := fieldsOf(typ)
+	columns := make(Columns, 0, len(plan.fields))
+	for _, field := range plan.fields {
+		column, err := columnOf(field)
+		if err != nil {
+			return Table{}, fmt.Errorf("field %s: %w", field.Field.Name, err)
+		}
+
+		columns = append(columns, column)
+	}
+
+	return Table{
+		Name:    name,
+		Schema:  schema,
+		Catalog: catalog,
+		Columns: columns,
+	}, nil
+}
+
+func columnOf(field value.StructField) (Column, error) {
+	_, nullable := value.NullableOf(field.Field.Type)
+	column := Column{
+		Name:     field.Name,
+		Type:     value.TypeOf(field.Field.Type),
+		Nullable: nullable || field.Field.Type.Kind() == reflect.Pointer,
+	}
+
+	var statements []Statement
+
+	for _, option := range field.Options {
+		key, val, _ := strings.Cut(option, "=")
+		switch key {
+		case "required":
+			column.Required = true
+		case "indexed":
+			column.Indexed = true
+		case "nullable":
+			column.Nullable = true
+		case "operators":
+			for _, name := range strings.Split(val, "|") {
+				statement, ok := operatorNames[name]
+				if !ok {
+					return column, fmt.Errorf("unknown operator %q", name)
+				}
+
+				statements = append(statements, statement)
+			}
+		default:
+			return column, fmt.Errorf("unknown tag option %q", key)
+		}
+	}
+
+	for _, statement := range statements {
+		column.Operators = append(column.Operators, Operator{
+			Statement:       statement,
+			ComparisonTypes: ComparisonTypes{VariableConstant},
+			Required:        column.Required,
+		})
+	}
+
+	return column, nil
+}
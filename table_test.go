@@ -0,0 +1,71 @@
+package lunodbgo
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+type category struct {
+	Name   string    `lunodb:"name"`
+	Parent *category `lunodb:"parent"`
+}
+
+func TestTableOfRecursiveStruct(t *testing.T) {
+	table, err := TableOf[category]("categories", "public", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	parent, ok := table.Columns.Find("parent")
+	if !ok {
+		t.Fatal("parent column not found")
+	}
+
+	if parent.Type.Kind != types.Record || !parent.Nullable {
+		t.Errorf("unexpected parent column: %s, nullable %t", types.Name(parent.Type), parent.Nullable)
+	}
+}
+
+type account struct {
+	Name     sql.NullString       `lunodb:"name"`
+	Balance  sql.NullInt64        `lunodb:"balance"`
+	Score    sql.NullFloat64      `lunodb:"score"`
+	Active   sql.NullBool         `lunodb:"active"`
+	Created  sql.NullTime         `lunodb:"created"`
+	Level    sql.NullByte         `lunodb:"level"`
+	Rank     sql.Null[int32]      `lunodb:"rank"`
+	Verified *sql.Null[time.Time] `lunodb:"verified"`
+	Email    string               `lunodb:"email"`
+}
+
+func TestTableOfNullable(t *testing.T) {
+	table, err := TableOf[account]("accounts", "public", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := Columns{
+		{Name: "name", Type: types.BasicString, Nullable: true},
+		{Name: "balance", Type: types.BasicInt64, Nullable: true},
+		{Name: "score", Type: types.BasicFloat64, Nullable: true},
+		{Name: "active", Type: types.BasicBool, Nullable: true},
+		{Name: "created", Type: types.BasicTimestamp, Nullable: true},
+		{Name: "level", Type: types.BasicUint8, Nullable: true},
+		{Name: "rank", Type: types.BasicInt32, Nullable: true},
+		{Name: "verified", Type: types.BasicTimestamp, Nullable: true},
+		{Name: "email", Type: types.BasicString},
+	}
+
+	if len(table.Columns) != len(expected) {
+		t.Fatalf("unexpected columns: %d", len(table.Columns))
+	}
+
+	for index, column := range table.Columns {
+		if column.Name != expected[index].Name || types.Name(column.Type) != types.Name(expected[index].Type) || column.Nullable != expected[index].Nullable {
+			t.Errorf("unexpected column %q: %s, nullable %t", column.Name, types.Name(column.Type), column.Nullable)
+		}
+	}
+}
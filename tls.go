@@ -0,0 +1,278 @@
+package lunodbgo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSConfig defines the transport security used when connecting to Stargate.
+// Certificate files are reloaded whenever they are modified on disk,
+// allowing certificates to be rotated without restarting the Connector.
+type TLSConfig struct {
+	// SystemRoots includes the system root pool when verifying Stargate
+	// certificates. The system root pool is always used if no CAFile is set.
+	SystemRoots bool
+	// CAFile is the path to a PEM encoded CA bundle used to verify Stargate.
+	CAFile string
+	// CertFile and KeyFile are the paths to a PEM encoded client certificate
+	// and private key presented to Stargate for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the server name used to verify Stargate.
+	ServerName string
+	// MinVersion is the minimum accepted TLS version.
+	MinVersion uint16
+}
+
+// WithSystemCertPool includes the system root pool when verifying Stargate
+// certificates in combination with a custom CA bundle.
+func WithSystemCertPool(enabled bool) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.TLS.SystemRoots = enabled
+		return nil
+	}
+}
+
+// WithCAFile sets the path to a PEM encoded CA bundle used to verify the
+// Stargate server certificates.
+func WithCAFile(path string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.TLS.CAFile = path
+		return nil
+	}
+}
+
+// WithClientCertificate sets the paths to a PEM encoded client certificate and
+// private key used for mutual TLS.
+func WithClientCertificate(certFile string, keyFile string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.TLS.CertFile = certFile
+		connector.TLS.KeyFile = keyFile
+		return nil
+	}
+}
+
+// WithServerName overrides the server name used to verify the Stargate server
+// certificates.
+func WithServerName(name string) ConnectorOption {
+	return func(connector *Connector) error {
+		connector.TLS.ServerName = name
+		return nil
+	}
+}
+
+// WithMinTLSVersion sets the minimum accepted TLS version (e.g. tls.VersionTLS13).
+func WithMinTLSVersion(version uint16) ConnectorOption {
+	return func(connector *Connector) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return fmt.Errorf("invalid tls version: %#x", version)
+		}
+
+		connector.TLS.MinVersion = version
+		return nil
+	}
+}
+
+// ParseTLSVersion parses the given TLS version (e.g. "1.2").
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid tls version: %q", version)
+	}
+}
+
+// tlsConfigFromEnv constructs a new TLS config from the LUNODB_TLS_*
+// environment variables.
+func tlsConfigFromEnv() (TLSConfig, error) {
+	config := TLSConfig{
+		SystemRoots: os.Getenv("LUNODB_TLS_SYSTEM_ROOTS") == "true",
+		CAFile:      os.Getenv("LUNODB_TLS_CA_FILE"),
+		CertFile:    os.Getenv("LUNODB_TLS_CERT_FILE"),
+		KeyFile:     os.Getenv("LUNODB_TLS_KEY_FILE"),
+		ServerName:  os.Getenv("LUNODB_TLS_SERVER_NAME"),
+		MinVersion:  tls.VersionTLS12,
+	}
+
+	version := os.Getenv("LUNODB_TLS_MIN_VERSION")
+	if version != "" {
+		var err error
+		config.MinVersion, err = ParseTLSVersion(version)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	return config, nil
+}
+
+// Config constructs a new tls.Config for the configured certificates. The
+// configured certificate files are loaded initially to report invalid
+// configurations early.
+func (config TLSConfig) Config() (*tls.Config, error) {
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errors.New("both a client certificate and key file have to be configured")
+	}
+
+	certificates := &certificates{config: config}
+	result := &tls.Config{
+		ServerName: config.ServerName,
+		MinVersion: config.MinVersion,
+	}
+
+	if config.CertFile != "" {
+		_, err := certificates.clientCertificate(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result.GetClientCertificate = certificates.clientCertificate
+	}
+
+	if config.CAFile != "" {
+		_, err := certificates.rootCAs()
+		if err != nil {
+			return nil, err
+		}
+
+		// NOTE: the default verification is replaced by verifyConnection to
+		// verify against the most recently loaded CA bundle.
+		result.InsecureSkipVerify = true
+		result.VerifyConnection = certificates.verifyConnection
+	}
+
+	return result, nil
+}
+
+// certificates loads and caches the configured certificate files. Files are
+// reloaded once their modification time changes.
+type certificates struct {
+	config TLSConfig
+
+	mu       sync.Mutex
+	roots    *x509.CertPool
+	rootsMod time.Time
+	cert     *tls.Certificate
+	certMod  time.Time
+	keyMod   time.Time
+}
+
+func (certificates *certificates) rootCAs() (*x509.CertPool, error) {
+	certificates.mu.Lock()
+	defer certificates.mu.Unlock()
+
+	modified, err := modTime(certificates.config.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if certificates.roots != nil && modified.Equal(certificates.rootsMod) {
+		return certificates.roots, nil
+	}
+
+	pool := x509.NewCertPool()
+	if certificates.config.SystemRoots {
+		pool, err = x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system cert pool: %w", err)
+		}
+	}
+
+	bundle, err := os.ReadFile(certificates.config.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificates found in CA file: %s", certificates.config.CAFile)
+	}
+
+	certificates.roots = pool
+	certificates.rootsMod = modified
+	return pool, nil
+}
+
+func (certificates *certificates) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certificates.mu.Lock()
+	defer certificates.mu.Unlock()
+
+	certMod, err := modTime(certificates.config.CertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	keyMod, err := modTime(certificates.config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if certificates.cert != nil && certMod.Equal(certificates.certMod) && keyMod.Equal(certificates.keyMod) {
+		return certificates.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certificates.config.CertFile, certificates.config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	certificates.cert = &cert
+	certificates.certMod = certMod
+	certificates.keyMod = keyMod
+	return certificates.cert, nil
+}
+
+func (certificates *certificates) verifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no server certificates presented")
+	}
+
+	name := certificates.config.ServerName
+	if name == "" {
+		name = state.ServerName
+	}
+
+	// NOTE: IP addresses are not included as server name, a server name has
+	// to be configured to verify Stargate when connecting through an IP.
+	if name == "" {
+		return errors.New("unable to verify server certificate without a server name")
+	}
+
+	roots, err := certificates.rootCAs()
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       name,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+
+	return err
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
@@ -0,0 +1,239 @@
+package lunodbgo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCertificate is a generated certificate together with its private key.
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCertificate generates a new certificate for the given name signed by
+// the given parent. The certificate is self-signed if no parent is given.
+func newTestCertificate(tb testing.TB, name string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	return &testCertificate{cert: cert, key: key}
+}
+
+// write writes the PEM encoded certificate and key to the given paths and
+// moves their modification time to the given time.
+func (certificate *testCertificate) write(tb testing.TB, certFile string, keyFile string, modified time.Time) {
+	err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.cert.Raw}), 0o600)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	err = os.Chtimes(certFile, modified, modified)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	if keyFile == "" {
+		return
+	}
+
+	key, err := x509.MarshalECPrivateKey(certificate.key)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}), 0o600)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+
+	err = os.Chtimes(keyFile, modified, modified)
+	if err != nil {
+		tb.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestTLSClientCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	now := time.Now()
+	first := newTestCertificate(t, "first", nil)
+	first.write(t, certFile, keyFile, now)
+
+	config, err := TLSConfig{CertFile: certFile, KeyFile: keyFile}.Config()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cert, err := config.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cert.Leaf.Subject.CommonName != "first" {
+		t.Fatalf("unexpected certificate: %s", cert.Leaf.Subject.CommonName)
+	}
+
+	second := newTestCertificate(t, "second", nil)
+	second.write(t, certFile, keyFile, now)
+
+	cert, err = config.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cert.Leaf.Subject.CommonName != "first" {
+		t.Fatalf("unexpected reload of an unmodified certificate: %s", cert.Leaf.Subject.CommonName)
+	}
+
+	second.write(t, certFile, keyFile, now.Add(time.Minute))
+
+	cert, err = config.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if cert.Leaf.Subject.CommonName != "second" {
+		t.Errorf("unexpected certificate: %s", cert.Leaf.Subject.CommonName)
+	}
+}
+
+func TestTLSVerifyConnection(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+
+	now := time.Now()
+	first := newTestCertificate(t, "first", nil)
+	second := newTestCertificate(t, "second", nil)
+	server := newTestCertificate(t, "stargate.test", second)
+
+	first.write(t, caFile, "", now)
+
+	config, err := TLSConfig{CAFile: caFile}.Config()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	state := tls.ConnectionState{
+		ServerName:       "stargate.test",
+		PeerCertificates: []*x509.Certificate{server.cert},
+	}
+
+	err = config.VerifyConnection(state)
+	if err == nil {
+		t.Fatal("expected a certificate signed by an unknown authority to be rejected")
+	}
+
+	second.write(t, caFile, "", now.Add(time.Minute))
+
+	err = config.VerifyConnection(state)
+	if err != nil {
+		t.Fatalf("unexpected error after reloading the CA bundle: %s", err)
+	}
+
+	state.ServerName = "other.test"
+	err = config.VerifyConnection(state)
+	if err == nil {
+		t.Fatal("expected a mismatching server name to be rejected")
+	}
+
+	state.ServerName = ""
+	err = config.VerifyConnection(state)
+	if err == nil {
+		t.Fatal("expected a connection without server name to be rejected")
+	}
+
+	config, err = TLSConfig{CAFile: caFile, ServerName: "stargate.test"}.Config()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = config.VerifyConnection(state)
+	if err != nil {
+		t.Fatalf("unexpected error using the configured server name: %s", err)
+	}
+}
+
+func TestTLSConfigFromEnv(t *testing.T) {
+	t.Setenv("LUNODB_TLS_SYSTEM_ROOTS", "true")
+	t.Setenv("LUNODB_TLS_CA_FILE", "ca.crt")
+	t.Setenv("LUNODB_TLS_CERT_FILE", "client.crt")
+	t.Setenv("LUNODB_TLS_KEY_FILE", "client.key")
+	t.Setenv("LUNODB_TLS_SERVER_NAME", "stargate.test")
+	t.Setenv("LUNODB_TLS_MIN_VERSION", "1.3")
+
+	config, err := tlsConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := TLSConfig{
+		SystemRoots: true,
+		CAFile:      "ca.crt",
+		CertFile:    "client.crt",
+		KeyFile:     "client.key",
+		ServerName:  "stargate.test",
+		MinVersion:  tls.VersionTLS13,
+	}
+
+	if config != expected {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	t.Setenv("LUNODB_TLS_MIN_VERSION", "")
+	config, err = tlsConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("unexpected default min version: %#x", config.MinVersion)
+	}
+
+	t.Setenv("LUNODB_TLS_MIN_VERSION", "1.4")
+	_, err = tlsConfigFromEnv()
+	if err == nil {
+		t.Error("expected an invalid tls version to be rejected")
+	}
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	lunopb "github.com/cloudproud/lunodb.api/proto/types"
 )
 
@@ -88,3 +90,63 @@ func NewArray(item *lunopb.Type) *lunopb.Type {
 		Underlying: (*lunopb.Type)(item),
 	}
 }
+
+// Assignable returns true if values of the given type can be assigned to a
+// column of the declared type. Any declared type accepts all values. Values
+// of type Any, such as NULL items, are assignable to all types.
+func Assignable(declared *lunopb.Type, typ *lunopb.Type) bool {
+	if declared == nil || declared.Kind == Any {
+		return true
+	}
+
+	if typ != nil && typ.Kind == Any {
+		return true
+	}
+
+	if typ == nil || declared.Kind != typ.Kind {
+		return false
+	}
+
+	if declared.Underlying != nil && !Assignable(declared.Underlying, typ.Underlying) {
+		return false
+	}
+
+	if len(declared.Items) == 0 {
+		return true
+	}
+
+	if len(declared.Items) != len(typ.Items) {
+		return false
+	}
+
+	for index := range declared.Items {
+		if !Assignable(declared.Items[index], typ.Items[index]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Name returns a human readable name of the given type (e.g. Array(String)).
+func Name(typ *lunopb.Type) string {
+	if typ == nil {
+		return Any.String()
+	}
+
+	name := typ.Kind.String()
+	if typ.Underlying != nil {
+		name += "(" + Name(typ.Underlying) + ")"
+	}
+
+	if len(typ.Items) > 0 {
+		items := make([]string, len(typ.Items))
+		for index, item := range typ.Items {
+			items[index] = Name(item)
+		}
+
+		name += "(" + strings.Join(items, ", ") + ")"
+	}
+
+	return name
+}
@@ -0,0 +1,279 @@
+package lunodbgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/planutil"
+)
+
+// ErrUnsupportedOperator is returned when a plan filters a column using an
+// operator or comparison type which has not been declared.
+var ErrUnsupportedOperator = errors.New("unsupported operator")
+
+// ErrRequiredPredicate is returned when a plan lacks a predicate required by
+// a column.
+var ErrRequiredPredicate = errors.New("required predicate missing")
+
+// validatePlan validates the given plan against the declared operators of the
+// targeted table. Columns without declared comparison operators fall back to
+// the comparison operators declared on the table, columns are unrestricted if
+// neither declares any. Plans targeting unknown tables are not validated.
+// Constants within the plan are not decoded.
+func validatePlan(literal *plan.Literal, tables Tables) error {
+	table, ok := tables.Find(literal.GetFrom().GetSchema(), literal.GetFrom().GetTable())
+	if !ok {
+		return nil
+	}
+
+	err := validateFilter(literal.GetFilter(), table)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range table.Columns {
+		err := validateRequired(column, literal.GetFilter())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateFilter validates all comparisons within the given filter, including
+// comparisons nested within OR expressions.
+func validateFilter(filter *plan.FilterExpression, table Table) error {
+	switch {
+	case filter == nil:
+		return nil
+	case filter.GetAndExpression() != nil:
+		return errors.Join(validateFilter(filter.GetAndExpression().Left, table), validateFilter(filter.GetAndExpression().Right, table))
+	case filter.GetOrExpression() != nil:
+		return errors.Join(validateFilter(filter.GetOrExpression().Left, table), validateFilter(filter.GetOrExpression().Right, table))
+	case filter.GetExpression().GetColumn() != nil:
+		return validateOperator(table, filter.GetExpression().GetColumn().Name, StatementEqual, VariableConstant)
+	case filter.GetComparisonExpression() != nil:
+		comparison := filter.GetComparisonExpression()
+		left, right := comparison.Left.GetExpression(), comparison.Right.GetExpression()
+
+		statement := Statement(comparison.Operator)
+		if comparison.SubOperator == node.Any && statement == StatementEqual {
+			statement = StatementIn
+		}
+
+		if column := left.GetColumn(); column != nil {
+			err := validateOperator(table, column.Name, statement, comparisonType(right))
+			if err != nil {
+				return err
+			}
+		}
+
+		if column := right.GetColumn(); column != nil {
+			return validateOperator(table, column.Name, flipped(statement), comparisonType(left))
+		}
+	}
+
+	return nil
+}
+
+// validateOperator validates whether the given operator and comparison type
+// have been declared for the given column.
+func validateOperator(table Table, name string, statement Statement, typ ComparisonType) error {
+	column, ok := table.Columns.Find(name)
+	if !ok {
+		return nil
+	}
+
+	operators := comparisonOperators(column, table)
+	if len(operators) == 0 {
+		return nil
+	}
+
+	index := slices.IndexFunc(operators, func(operator Operator) bool {
+		return operator.Statement == statement
+	})
+
+	if index < 0 {
+		return fmt.Errorf("%w: column %q does not support %s, supported operators are %s", ErrUnsupportedOperator, name, statement, operatorList(operators))
+	}
+
+	comparisons := operators[index].ComparisonTypes
+	if len(comparisons) > 0 && !slices.Contains(comparisons, typ) {
+		return fmt.Errorf("%w: column %q does not support %s against a %s", ErrUnsupportedOperator, name, statement, typ)
+	}
+
+	return nil
+}
+
+// validateRequired validates whether the plan contains the predicates
+// required by the given column. Required operators are alternatives, a single
+// predicate using any of them satisfies the requirement. Predicates nested
+// within OR expressions do not satisfy the requirement.
+//
+// NOTE: requirements are declared per column, operators inherited from the
+// table never require a predicate.
+func validateRequired(column Column, filter *plan.FilterExpression) error {
+	var required []Operator
+	for _, operator := range column.Operators {
+		if operator.Required && operator.Statement.IsComparison() {
+			required = append(required, operator)
+		}
+	}
+
+	if len(required) == 0 && !column.Required {
+		return nil
+	}
+
+	statements := constraints(filter, column.Name)
+	if len(required) == 0 {
+		if len(statements) > 0 {
+			return nil
+		}
+
+		return fmt.Errorf("%w: column %q has to be filtered", ErrRequiredPredicate, column.Name)
+	}
+
+	for _, statement := range statements {
+		if slices.ContainsFunc(required, func(operator Operator) bool { return operator.Statement == statement }) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: column %q has to be filtered using %s", ErrRequiredPredicate, column.Name, operatorList(required))
+}
+
+// constraints returns the operators of the conjunctions within the given
+// filter comparing the given column against an expression without columns,
+// such as a constant or parameter.
+func constraints(filter *plan.FilterExpression, name string) []Statement {
+	var result []Statement
+	for _, conjunction := range conjunctions(filter) {
+		column, statement, ok := constraintOf(conjunction)
+		if ok && column == name {
+			result = append(result, statement)
+		}
+	}
+
+	return result
+}
+
+// conjunctions returns the conjunctions at the top level of the given filter.
+func conjunctions(filter *plan.FilterExpression) []*plan.FilterExpression {
+	if filter == nil {
+		return nil
+	}
+
+	if and := filter.GetAndExpression(); and != nil {
+		return append(conjunctions(and.Left), conjunctions(and.Right)...)
+	}
+
+	return []*plan.FilterExpression{filter}
+}
+
+// constraintOf returns the column and operator of the given filter if it
+// compares a column against an expression without columns. Comparisons with
+// the column on the right are flipped and = ANY is represented as IN.
+func constraintOf(filter *plan.FilterExpression) (string, Statement, bool) {
+	if column := filter.GetExpression().GetColumn(); column != nil {
+		return column.Name, StatementEqual, true
+	}
+
+	comparison := filter.GetComparisonExpression()
+	if comparison == nil {
+		return "", 0, false
+	}
+
+	operator := comparison.Operator
+	left, right := comparison.Left.GetExpression(), comparison.Right.GetExpression()
+	if left.GetColumn() == nil {
+		flipped, ok := planutil.Flip(operator)
+		if !ok || comparison.SubOperator != node.OperatorUnknown {
+			return "", 0, false
+		}
+
+		operator, left, right = flipped, right, left
+	}
+
+	if left.GetColumn() == nil || hasColumn(right) {
+		return "", 0, false
+	}
+
+	switch {
+	case comparison.SubOperator == node.Any && operator == node.Equal:
+		operator = node.In
+	case comparison.SubOperator != node.OperatorUnknown:
+		return "", 0, false
+	}
+
+	return left.GetColumn().Name, Statement(operator), true
+}
+
+// comparisonOperators returns the comparison operators declared on the given
+// column, falling back to the comparison operators declared on the table.
+func comparisonOperators(column Column, table Table) Operators {
+	operators := slices.DeleteFunc(slices.Clone(column.Operators), func(operator Operator) bool {
+		return !operator.Statement.IsComparison()
+	})
+
+	if len(operators) > 0 {
+		return operators
+	}
+
+	return slices.DeleteFunc(slices.Clone(table.Operators), func(operator Operator) bool {
+		return !operator.Statement.IsComparison()
+	})
+}
+
+// comparisonType returns the comparison type of a column compared against the
+// given expression.
+func comparisonType(expr *plan.Expression) ComparisonType {
+	if hasColumn(expr) {
+		return VariableVariable
+	}
+
+	return VariableConstant
+}
+
+func hasColumn(expr *plan.Expression) bool {
+	switch {
+	case expr == nil:
+		return false
+	case expr.GetColumn() != nil:
+		return true
+	case expr.GetCastExpression() != nil:
+		return hasColumn(expr.GetCastExpression().Expression)
+	case expr.GetBinaryExpression() != nil:
+		return hasColumn(expr.GetBinaryExpression().Left) || hasColumn(expr.GetBinaryExpression().Right)
+	case expr.GetFunction() != nil:
+		return slices.ContainsFunc(expr.GetFunction().Expressions, hasColumn)
+	case expr.GetTuple() != nil:
+		return slices.ContainsFunc(expr.GetTuple().Expressions, hasColumn)
+	}
+
+	return false
+}
+
+// flipped returns the equivalent of the given comparison with the operands
+// swapped. Operators without such an equivalent are returned as-is.
+func flipped(statement Statement) Statement {
+	operator, ok := planutil.Flip(node.OperatorStatement(statement))
+	if !ok {
+		return statement
+	}
+
+	return Statement(operator)
+}
+
+func operatorList(operators Operators) string {
+	names := make([]string, len(operators))
+	for index, operator := range operators {
+		names[index] = operator.Statement.String()
+	}
+
+	return strings.Join(names, ", ")
+}
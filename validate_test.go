@@ -0,0 +1,99 @@
+package lunodbgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudproud/lunodb.api/proto/node"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+func columnExpr(name string) *plan.Expression {
+	return &plan.Expression{Statement: &plan.Expression_Column{Column: &plan.Column{Name: name}}}
+}
+
+func constantExpr(typ *lunopb.Type, val []byte) *plan.Expression {
+	return &plan.Expression{Statement: &plan.Expression_Constant{Constant: &plan.Constant{Type: typ, Value: val}}}
+}
+
+func compareFilter(operator node.OperatorStatement, left *plan.Expression, right *plan.Expression) *plan.FilterExpression {
+	return &plan.FilterExpression{Condition: &plan.FilterExpression_ComparisonExpression{ComparisonExpression: &plan.ComparisonExpression{
+		Operator: operator,
+		Left:     &plan.FilterExpression{Condition: &plan.FilterExpression_Expression{Expression: left}},
+		Right:    &plan.FilterExpression{Condition: &plan.FilterExpression_Expression{Expression: right}},
+	}}}
+}
+
+func TestValidatePlanUndecodableConstant(t *testing.T) {
+	tables := Tables{{
+		Name:   "weather",
+		Schema: "public",
+		Columns: Columns{{
+			Name: "city",
+			Type: types.BasicString,
+			Operators: Operators{
+				{Statement: StatementEqual, ComparisonTypes: ComparisonTypes{VariableConstant}, Required: true},
+			},
+		}},
+	}}
+
+	literal := &plan.Literal{
+		From:   &plan.From{Schema: "public", Table: "weather"},
+		Filter: compareFilter(node.Equal, columnExpr("city"), constantExpr(types.BasicAny, []byte{0xff})),
+	}
+
+	err := validatePlan(literal, tables)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	literal.Filter = compareFilter(node.GreaterThan, constantExpr(types.BasicAny, []byte{0xff}), columnExpr("city"))
+	err = validatePlan(literal, tables)
+	if !errors.Is(err, ErrUnsupportedOperator) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	literal.Filter = nil
+	err = validatePlan(literal, tables)
+	if !errors.Is(err, ErrRequiredPredicate) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidatePlanTableRequiredOperator(t *testing.T) {
+	tables := Tables{{
+		Name:   "weather",
+		Schema: "public",
+		Operators: Operators{
+			{Statement: StatementEqual, Required: true},
+			{Statement: StatementGreaterThan},
+		},
+		Columns: Columns{
+			{Name: "city", Type: types.BasicString},
+			{Name: "temperature", Type: types.BasicInt64},
+		},
+	}}
+
+	literal := &plan.Literal{
+		From: &plan.From{Schema: "public", Table: "weather"},
+	}
+
+	err := validatePlan(literal, tables)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	literal.Filter = compareFilter(node.GreaterThan, columnExpr("temperature"), constantExpr(types.BasicInt64, make([]byte, 8)))
+	err = validatePlan(literal, tables)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	literal.Filter = compareFilter(node.LessThan, columnExpr("temperature"), constantExpr(types.BasicInt64, make([]byte, 8)))
+	err = validatePlan(literal, tables)
+	if !errors.Is(err, ErrUnsupportedOperator) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
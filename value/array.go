@@ -1,25 +1,95 @@
 package value
 
-func EncodeArray[T any](val []T, buf []byte) ([]byte, error) {
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// EncodeArray encodes the given slice as an array. Arrays are encoded as the
+// number of elements (uint32) followed by a length-prefixed (uint32) frame for
+// every element. NULL elements are encoded as an empty frame.
+func EncodeArray[T any](val []T, buf []byte) (_ []byte, err error) {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(val)))
+	for index := range val {
+		buf, err = encodeFrame(val[index], buf)
+		if err != nil {
+			return buf, fmt.Errorf("array element %d: %w", index, err)
+		}
+	}
+
+	return buf, nil
+}
+
+// encodeFrame appends the given value as a length-prefixed (uint32) frame.
+func encodeFrame(val any, buf []byte) (_ []byte, err error) {
+	offset := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+
+	_, buf, err = Encode(val, buf)
+	if err != nil {
+		return buf[:offset], err
+	}
+
+	binary.BigEndian.PutUint32(buf[offset:], uint32(len(buf)-offset-4))
 	return buf, nil
 }
 
-// func (a Array) Encode(buf []byte) ([]byte, error) {
-// 	if a.Items == nil {
-// 		return buf, nil
-// 	}
+// encodeSlice encodes the given reflected slice or array as an array. The
+// element type is derived from the slice type, values of interface slices
+// (e.g. []any) have to share the same type.
+func encodeSlice(rv reflect.Value, buf []byte) (_ *lunopb.Type, _ []byte, err error) {
+	offset := len(buf)
+	underlying := TypeOf(rv.Type().Elem())
 
-// 	buf = binary.BigEndian.AppendUint32(buf, uint32(len(a.Items)))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(rv.Len()))
+	for index := range rv.Len() {
+		frame := len(buf)
+		buf = append(buf, 0, 0, 0, 0)
 
-// 	for index := range a.Items {
-// 		frame, err := Encode(a.underlying, a.Items[index])
-// 		if err != nil {
-// 			return nil, err
-// 		}
+		var typ *lunopb.Type
+		typ, buf, err = Encode(rv.Index(index).Interface(), buf)
+		if err != nil {
+			return types.BasicAny, buf[:offset], fmt.Errorf("array element %d: %w", index, err)
+		}
 
-// 		buf = binary.BigEndian.AppendUint32(buf, uint32(len(frame)))
-// 		buf = append(buf, frame...)
-// 	}
+		binary.BigEndian.PutUint32(buf[frame:], uint32(len(buf)-frame-4))
 
-// 	return buf, nil
-// }
+		if underlying.Kind == types.Any && len(buf)-frame > 4 {
+			underlying = typ
+			continue
+		}
+
+		if !types.Assignable(underlying, typ) && len(buf)-frame > 4 {
+			return types.BasicAny, buf[:offset], fmt.Errorf("array element %d: mixed element types %s and %s", index, types.Name(underlying), types.Name(typ))
+		}
+	}
+
+	return types.NewArray(underlying), buf, nil
+}
+
+// DecodeArray decodes an array of the given element type from the front of
+// the buffer. NULL elements are decoded as nil.
+func DecodeArray(underlying *lunopb.Type, buf []byte) ([]any, []byte, error) {
+	count, buf, err := DecodeUint32(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	if uint64(count)*4 > uint64(len(buf)) {
+		return nil, buf, ErrShortBuffer
+	}
+
+	result := make([]any, count)
+	for index := range result {
+		result[index], buf, err = decodeFrame(underlying, buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("array element %d: %w", index, err)
+		}
+	}
+
+	return result, buf, nil
+}
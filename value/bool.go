@@ -28,3 +28,13 @@ func EncodeBool[T bool | *bool](val T, buf []byte) ([]byte, error) {
 
 	return buf, nil
 }
+
+// DecodeBool decodes a boolean from the front of the buffer.
+func DecodeBool(buf []byte) (bool, []byte, error) {
+	val, buf, err := take(buf, 1)
+	if err != nil {
+		return false, buf, err
+	}
+
+	return val[0] != 0, buf, nil
+}
@@ -0,0 +1,86 @@
+package value
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// EncodeBytes encodes the given bytes as the number of bytes (uint64)
+// followed by the bytes themselves.
+func EncodeBytes[T []byte | *[]byte | json.RawMessage | *json.RawMessage](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case []byte:
+		return appendBytes(v, buf), nil
+	case *[]byte:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendBytes(*v, buf), nil
+	case json.RawMessage:
+		return appendBytes(v, buf), nil
+	case *json.RawMessage:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendBytes(*v, buf), nil
+	default:
+		return buf, fmt.Errorf("unsupported bytes type: %T", val)
+	}
+}
+
+func appendBytes(val []byte, buf []byte) []byte {
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(val)))
+	return append(buf, val...)
+}
+
+// EncodeReader reads the given reader until EOF and encodes its contents as
+// bytes. The contents are read directly into the buffer, allowing large blobs
+// to be encoded without intermediate copies. Nil readers, including nil
+// pointers such as a nil *os.File, are encoded as NULL.
+func EncodeReader(val io.Reader, buf []byte) ([]byte, error) {
+	if val == nil {
+		return buf, nil
+	}
+
+	if rv := reflect.ValueOf(val); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return buf, nil
+	}
+
+	offset := len(buf)
+	buf = binary.BigEndian.AppendUint64(buf, 0)
+
+	for {
+		if len(buf) == cap(buf) {
+			buf = append(buf, 0)[:len(buf)]
+		}
+
+		n, err := val.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return buf[:offset], err
+		}
+	}
+
+	binary.BigEndian.PutUint64(buf[offset:], uint64(len(buf)-offset-8))
+	return buf, nil
+}
+
+// DecodeBytes decodes bytes from the front of the buffer. The returned bytes
+// are a copy of the buffer.
+func DecodeBytes(buf []byte) ([]byte, []byte, error) {
+	val, buf, err := decodeLength(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	return append([]byte{}, val...), buf, nil
+}
@@ -0,0 +1,40 @@
+package value
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEncodeNilReader(t *testing.T) {
+	tests := map[string]io.Reader{
+		"*bytes.Buffer":   (*bytes.Buffer)(nil),
+		"*strings.Reader": (*strings.Reader)(nil),
+		"*os.File":        (*os.File)(nil),
+	}
+
+	for name, reader := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, buf, err := Encode(reader, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(buf) != 0 {
+				t.Errorf("unexpected non-null value: %x", buf)
+			}
+		})
+	}
+
+	_, buf, err := Encode(strings.NewReader("lunodb"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []byte{0, 0, 0, 0, 0, 0, 0, 6, 'l', 'u', 'n', 'o', 'd', 'b'}
+	if !bytes.Equal(buf, expected) {
+		t.Errorf("unexpected encoding: %x, expected %x", buf, expected)
+	}
+}
@@ -9,75 +9,216 @@ import (
 
 type definition struct {
 	Encoder     string
+	Kind        string
 	Type        string
 	Package     string
 	NotNullable bool
+	NoSlice     bool
+	// Sample is an expression of a sample value used by the generated round
+	// trip test. Decoded is the expression of the decoded sample, defaults to
+	// the sample.
+	Sample  string
+	Decoded string
+	// Unframed is set for values consuming the remainder of the buffer when
+	// decoded.
+	Unframed bool
+	// NullTypes are nil-able types matching the type which are encoded as
+	// NULL when nil.
+	NullTypes []string
 }
 
 var types = []definition{
-	{Encoder: "String", Type: "string"},
-	{Encoder: "Bool", Type: "bool"},
-	{Encoder: "Int8", Type: "int8"},
-	{Encoder: "Int16", Type: "int16"},
-	{Encoder: "Int32", Type: "int32"},
-	{Encoder: "Int64", Type: "int64"},
-	{Encoder: "Int64", Type: "int"},
-	{Encoder: "Uint8", Type: "uint8"},
-	{Encoder: "Uint16", Type: "uint16"},
-	{Encoder: "Uint32", Type: "uint32"},
-	{Encoder: "Uint64", Type: "uint64"},
-	{Encoder: "Float32", Type: "float32"},
-	{Encoder: "Float64", Type: "float64"},
-	// {Encoder: "Time", Type: "Time", Package: "time"},
-	// {Encoder: "Duration", Type: "Duration", Package: "duration"},
-	{Encoder: "Inet", Type: "Prefix", Package: "net/netip"},
-	{Encoder: "UUID", Type: "[16]byte", NotNullable: true},
-	// {Encoder: "Byte", Type: "[]byte", NotNullable: true},
-	{Encoder: "Object", Type: "map[string]any", NotNullable: true},
+	{Encoder: "String", Type: "string", Sample: `"lunodb"`},
+	{Encoder: "Bool", Type: "bool", Sample: "true"},
+	{Encoder: "Int8", Type: "int8", Sample: "int8(-8)"},
+	{Encoder: "Int16", Type: "int16", Sample: "int16(-16)"},
+	{Encoder: "Int32", Type: "int32", Sample: "int32(-32)"},
+	{Encoder: "Int64", Type: "int64", Sample: "int64(-64)"},
+	{Encoder: "Int64", Type: "int", Sample: "int(-42)", Decoded: "int64(-42)"},
+	{Encoder: "Uint8", Type: "uint8", NoSlice: true, Sample: "uint8(8)"}, // NOTE: []uint8 is encoded as bytes
+	{Encoder: "Uint16", Type: "uint16", Sample: "uint16(16)"},
+	{Encoder: "Uint32", Type: "uint32", Sample: "uint32(32)"},
+	{Encoder: "Uint64", Type: "uint64", Sample: "uint64(64)"},
+	{Encoder: "Float32", Type: "float32", Sample: "float32(1.5)"},
+	{Encoder: "Float64", Type: "float64", Sample: "float64(2.25)"},
+	{Encoder: "Timestamp", Type: "Time", Package: "time", Sample: "time.Date(2024, 2, 29, 13, 4, 5, 6, time.UTC)"},
+	{Encoder: "Date", Type: "Date", Sample: "NewDate(2024, 2, 29)"},
+	{Encoder: "Time", Type: "Time", Sample: "NewTime(13, 4, 5, 6)"},
+	{Encoder: "Duration", Type: "Duration", Sample: "Duration{Months: 1, Days: 2, Nanos: 3}"},
+	{Encoder: "Duration", Type: "Duration", Package: "time", Sample: "90 * time.Second", Decoded: "Duration{Nanos: int64(90 * time.Second)}"},
+	{Encoder: "Decimal", Kind: "String", Type: "Decimal", Sample: "NewDecimal(big.NewInt(-12345), 2)", Decoded: `"-123.45"`}, // NOTE: decimals are encoded as strings
+	{Encoder: "Decimal", Kind: "String", Type: "Rat", Package: "math/big", Sample: "*big.NewRat(1, 4)", Decoded: `"0.25"`},
+	{Encoder: "Decimal", Kind: "String", Type: "Int", Package: "math/big", Sample: "*big.NewInt(42)", Decoded: `"42"`},
+	{Encoder: "Inet", Type: "Prefix", Package: "net/netip", Sample: `netip.MustParsePrefix("10.0.0.0/8")`, Unframed: true},
+	{Encoder: "Inet", Type: "Addr", Package: "net/netip", Sample: `netip.MustParseAddr("10.0.0.1")`, Decoded: `netip.MustParsePrefix("10.0.0.1/32")`, Unframed: true},
+	{Encoder: "Inet", Type: "IP", Package: "net", Sample: `net.ParseIP("10.0.0.1")`, Decoded: `netip.MustParsePrefix("10.0.0.1/32")`, Unframed: true},
+	{Encoder: "Inet", Type: "IPNet", Package: "net", Sample: "net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}", Decoded: `netip.MustParsePrefix("10.0.0.0/8")`, Unframed: true},
+	{Encoder: "UUID", Type: "[16]byte", NotNullable: true, Sample: "[16]byte{1, 2, 3}"},
+	{Encoder: "Bytes", Type: "[]byte", Sample: `[]byte("lunodb")`},
+	{Encoder: "Bytes", Type: "RawMessage", Package: "encoding/json", Sample: `json.RawMessage("{}")`, Decoded: `[]byte("{}")`},
+	{Encoder: "Reader", Kind: "Bytes", Type: "Reader", Package: "io", NotNullable: true, NoSlice: true, Sample: `io.Reader(strings.NewReader("lunodb"))`, Decoded: `[]byte("lunodb")`, NullTypes: []string{"*bytes.Buffer", "*strings.Reader", "*os.File"}},
+	{Encoder: "Object", Type: "map[string]any", NotNullable: true, Sample: `map[string]any{"key": int64(1)}`},
 }
 
 var tmpl = `// Code generated by go generate; DO NOT EDIT.
 package value
 
 import (
-	"fmt"
-	{{ range $pkg, $alias := .Packages }}
+	{{- range $pkg, $alias := .Packages }}
 	{{ $alias }} "{{ $pkg }}"
 	{{- end }}
+	"reflect"
 
 	"github.com/cloudproud/lunodb.go/types"
 	lunopb "github.com/cloudproud/lunodb.api/proto/types"
 )
 
-func Encode(val any, buf []byte) (_ *lunopb.Type, _ []byte, err error) {
+// knownTypes maps the types supported by Encode directly onto the type of
+// their encoded values.
+var knownTypes = map[reflect.Type]*lunopb.Type{
+	{{- range .Types }}
+	reflect.TypeFor[{{.Type}}](): types.Basic{{.Kind}},
+	{{- end }}
+}
+
+func Encode(val any, buf []byte) (*lunopb.Type, []byte, error) {
+	typ, buf, ok, err := encodeKnown(val, buf)
+	if ok {
+		return typ, buf, err
+	}
+
+	return encodeReflect(val, buf)
+}
+
+// encodeKnown encodes values of the types supported by Encode directly. False
+// is returned for any other type.
+func encodeKnown(val any, buf []byte) (_ *lunopb.Type, _ []byte, ok bool, err error) {
 	switch v := val.(type) {
+	case nil:
+		return types.BasicAny, buf, true, nil
 	{{- range .Types }}
 	case {{.Type}}:
 		buf, err = Encode{{.Encoder}}(v, buf)
-		return types.Basic{{.Encoder}}, buf, err
+		return types.Basic{{.Kind}}, buf, true, err
 	{{- end }}
 	{{- range .Types }}
 	{{- if not .NotNullable }}
 	case *{{.Type}}:
 		buf, err = Encode{{.Encoder}}(v, buf)
-		return types.Basic{{.Encoder}}, buf, err
+		return types.Basic{{.Kind}}, buf, true, err
 	{{- end }}
 	{{- end }}
 	{{- range .Types }}
+	{{- if not .NoSlice }}
 	case []{{.Type}}:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.Basic{{.Encoder}}), buf, err
+		return types.NewArray(types.Basic{{.Kind}}), buf, true, err
+	{{- end }}
 	{{- end }}
 	{{- range .Types }}
 	{{- if not .NotNullable }}
 	case []*{{.Type}}:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.Basic{{.Encoder}}), buf, err
+		return types.NewArray(types.Basic{{.Kind}}), buf, true, err
 	{{- end }}
 	{{- end }}
 	}
 
-	return types.BasicAny, buf, fmt.Errorf("unsupported type: %T", val)
+	return nil, buf, false, nil
+}
+`
+
+var testTmpl = `// Code generated by go generate; DO NOT EDIT.
+package value
+
+import (
+	{{- range $pkg, $alias := .Packages }}
+	{{ $alias }} "{{ $pkg }}"
+	{{- end }}
+
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ref[T any](val T) *T {
+	return &val
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      any
+		expected any
+		unframed bool
+		null     bool
+	}{
+	{{- range .Types }}
+		{name: "{{.Type}}", val: {{.Sample}}, expected: {{.Decoded}}{{if .Unframed}}, unframed: true{{end}}},
+	{{- end }}
+	{{- range .Types }}
+	{{- if not .NotNullable }}
+		{name: "*{{.Type}}", val: ref({{.Sample}}), expected: {{.Decoded}}{{if .Unframed}}, unframed: true{{end}}},
+	{{- end }}
+	{{- end }}
+	{{- range .Types }}
+	{{- if not .NoSlice }}
+		{name: "[]{{.Type}}", val: []{{.Type}}{ {{.Sample}} }, expected: []any{ {{.Decoded}} }},
+	{{- end }}
+	{{- end }}
+	{{- range .Types }}
+	{{- if not .NotNullable }}
+		{name: "[]*{{.Type}}", val: []*{{.Type}}{ref({{.Sample}}), nil}, expected: []any{ {{.Decoded}}, nil}},
+	{{- end }}
+	{{- end }}
+	{{- range .Types }}
+	{{- range .NullTypes }}
+		{name: "nil {{.}}", val: ({{.}})(nil), null: true},
+		{name: "[]{{.}}", val: []{{.}}{nil}, expected: []any{nil}},
+	{{- end }}
+	{{- end }}
+	}
+
+	trailer := []byte{0xde, 0xad}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typ, buf, err := Encode(test.val, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.null {
+				if len(buf) != 0 {
+					t.Errorf("unexpected non-null value: %x", buf)
+				}
+
+				return
+			}
+
+			if !test.unframed {
+				buf = append(buf, trailer...)
+			}
+
+			val, rest, err := Decode(typ, buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(val, test.expected) {
+				t.Errorf("unexpected value: %#v, expected %#v", val, test.expected)
+			}
+
+			if test.unframed && len(rest) != 0 {
+				t.Errorf("unexpected remainder: %x", rest)
+			}
+
+			if !test.unframed && !bytes.Equal(rest, trailer) {
+				t.Errorf("unexpected remainder: %x, expected %x", rest, trailer)
+			}
+		})
+	}
 }
 `
 
@@ -94,16 +235,16 @@ func main() {
 }
 
 func run() error {
-	t := template.Must(template.New("switch").Parse(tmpl))
-
-	f, err := os.Create("encoder_gen.go")
-	if err != nil {
-		return err
-	}
-	defer f.Close() //nolint:errcheck
-
 	packages := make(map[string]string)
 	for index, typ := range types {
+		if typ.Kind == "" {
+			types[index].Kind = typ.Encoder
+		}
+
+		if typ.Decoded == "" {
+			types[index].Decoded = typ.Sample
+		}
+
 		if typ.Package == "" {
 			continue
 		}
@@ -113,8 +254,27 @@ func run() error {
 		types[index].Type = fmt.Sprintf("%s.%s", packages[typ.Package], typ.Type)
 	}
 
-	return t.Execute(f, TemplateData{
+	data := TemplateData{
 		Types:    types,
 		Packages: packages,
-	})
+	}
+
+	err := generate("encoder_gen.go", tmpl, data)
+	if err != nil {
+		return err
+	}
+
+	return generate("encoder_gen_test.go", testTmpl, data)
+}
+
+func generate(name string, text string, data TemplateData) error {
+	t := template.Must(template.New(name).Parse(text))
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	return t.Execute(f, data)
 }
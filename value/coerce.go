@@ -0,0 +1,219 @@
+package value
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// Coerce converts the given value into a value of the declared type if the
+// conversion is lossless, such as widening integers, int to int64, string to
+// UUID or Inet and time.Time to Timestamp, Date or Time. Values which do not
+// require a conversion are returned as-is. An error is returned if a
+// conversion would lose data.
+func Coerce(typ *lunopb.Type, val any) (any, error) {
+	if typ == nil || val == nil {
+		return val, nil
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return val, nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch typ.Kind {
+	case types.Int8:
+		return coerceInt(rv, val, math.MinInt8, math.MaxInt8, func(v int64) any { return int8(v) })
+	case types.Int16:
+		return coerceInt(rv, val, math.MinInt16, math.MaxInt16, func(v int64) any { return int16(v) })
+	case types.Int32:
+		return coerceInt(rv, val, math.MinInt32, math.MaxInt32, func(v int64) any { return int32(v) })
+	case types.Int64:
+		return coerceInt(rv, val, math.MinInt64, math.MaxInt64, func(v int64) any { return v })
+	case types.Uint8:
+		return coerceUint(rv, val, math.MaxUint8, func(v uint64) any { return uint8(v) })
+	case types.Uint16:
+		return coerceUint(rv, val, math.MaxUint16, func(v uint64) any { return uint16(v) })
+	case types.Uint32:
+		return coerceUint(rv, val, math.MaxUint32, func(v uint64) any { return uint32(v) })
+	case types.Uint64:
+		return coerceUint(rv, val, math.MaxUint64, func(v uint64) any { return v })
+	case types.Float64:
+		if rv.Kind() == reflect.Float32 {
+			return rv.Float(), nil
+		}
+	case types.UUID:
+		if rv.Kind() == reflect.String {
+			return ParseUUID(rv.String())
+		}
+
+		if rv.Kind() == reflect.Array && rv.Len() == 16 && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Convert(reflect.TypeFor[[16]byte]()).Interface(), nil
+		}
+	case types.Inet:
+		if rv.Kind() == reflect.String {
+			return ParseInet(rv.String())
+		}
+	case types.Timestamp:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return t, nil
+		}
+	case types.Date:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return Date(t), nil
+		}
+	case types.Time:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return Time(t), nil
+		}
+	case types.Tuple, types.Record:
+		return coerceItems(typ.Items, rv.Interface())
+	}
+
+	return val, nil
+}
+
+func coerceInt(rv reflect.Value, val any, minimum int64, maximum int64, convert func(int64) any) (any, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := rv.Int()
+		if v < minimum || v > maximum {
+			return val, fmt.Errorf("value %d overflows the range [%d, %d]", v, minimum, maximum)
+		}
+
+		return convert(v), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := rv.Uint()
+		if v > uint64(maximum) {
+			return val, fmt.Errorf("value %d overflows the range [%d, %d]", v, minimum, maximum)
+		}
+
+		return convert(int64(v)), nil
+	}
+
+	return val, nil
+}
+
+func coerceUint(rv reflect.Value, val any, maximum uint64, convert func(uint64) any) (any, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := rv.Int()
+		if v < 0 || uint64(v) > maximum {
+			return val, fmt.Errorf("value %d overflows the range [0, %d]", v, maximum)
+		}
+
+		return convert(uint64(v)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := rv.Uint()
+		if v > maximum {
+			return val, fmt.Errorf("value %d overflows the range [0, %d]", v, maximum)
+		}
+
+		return convert(v), nil
+	}
+
+	return val, nil
+}
+
+// coerceItems coerces the items of the given tuple or record into the
+// declared item types.
+func coerceItems(items []*lunopb.Type, val any) (any, error) {
+	switch v := val.(type) {
+	case Tuple:
+		if len(v) != len(items) {
+			return val, nil
+		}
+
+		result := make(Tuple, len(v))
+		for index := range v {
+			item, err := Coerce(items[index], v[index])
+			if err != nil {
+				return val, fmt.Errorf("item %d: %w", index, err)
+			}
+
+			result[index] = item
+		}
+
+		return result, nil
+	case Record:
+		if len(v) != len(items) {
+			return val, nil
+		}
+
+		result := make(Record, len(v))
+		for index := range v {
+			item, err := Coerce(items[index], v[index].Value)
+			if err != nil {
+				return val, fmt.Errorf("field %q: %w", v[index].Name, err)
+			}
+
+			result[index] = Field{Name: v[index].Name, Value: item}
+		}
+
+		return result, nil
+	}
+
+	return val, nil
+}
+
+// EncodeAs encodes the given value as the declared type. The value is coerced
+// into the declared type when possible. An error is returned if the value can
+// not be represented as the declared type.
+func EncodeAs(typ *lunopb.Type, val any, buf []byte) ([]byte, error) {
+	if val == nil {
+		return buf, nil
+	}
+
+	// NOTE: values which already encode as the declared type are not coerced,
+	// coercing boxes the converted value and allocates for every value.
+	if !types.Assignable(typ, TypeOf(reflect.TypeOf(val))) {
+		coerced, err := Coerce(typ, val)
+		if err != nil {
+			return buf, err
+		}
+
+		val = coerced
+	}
+
+	offset := len(buf)
+	actual, buf, err := Encode(val, buf)
+	if err != nil {
+		return buf[:offset], err
+	}
+
+	if types.Assignable(typ, actual) {
+		return buf, nil
+	}
+
+	// NOTE: the type of custom values is only known once encoded, these are
+	// coerced from their decoded value since encoding them might not be
+	// repeatable, such as readers and valuers.
+	decoded, _, err := Decode(actual, buf[offset:])
+	if err != nil {
+		return buf[:offset], err
+	}
+
+	coerced, err := Coerce(typ, decoded)
+	if err != nil {
+		return buf[:offset], err
+	}
+
+	actual, buf, err = Encode(coerced, buf[:offset])
+	if err != nil {
+		return buf[:offset], err
+	}
+
+	if !types.Assignable(typ, actual) {
+		return buf[:offset], fmt.Errorf("cannot use %T as %s", val, types.Name(typ))
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,89 @@
+package value
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// countingValuer counts the number of times it has been converted.
+type countingValuer struct {
+	val   any
+	calls *int
+}
+
+func (valuer countingValuer) LunoValue() (any, error) {
+	*valuer.calls++
+	return valuer.val, nil
+}
+
+func TestEncodeAsValuerOnce(t *testing.T) {
+	calls := 0
+	buf, err := EncodeAs(types.BasicInt32, countingValuer{val: int64(42), calls: &calls}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("unexpected number of conversions: %d", calls)
+	}
+
+	val, _, err := DecodeInt32(buf)
+	if err != nil || val != 42 {
+		t.Errorf("unexpected value: %v, %v", val, err)
+	}
+}
+
+func TestEncodeAs(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      *lunopb.Type
+		val      any
+		expected any
+	}{
+		{name: "int", typ: types.BasicInt64, val: 42, expected: int64(42)},
+		{name: "int16", typ: types.BasicInt32, val: int16(-7), expected: int32(-7)},
+		{name: "sql.NullInt32", typ: types.BasicInt32, val: sql.NullInt32{Int32: 7, Valid: true}, expected: int32(7)},
+		{name: "sql.NullByte", typ: types.BasicUint8, val: sql.NullByte{Byte: 7, Valid: true}, expected: uint8(7)},
+		{name: "date", typ: types.BasicDate, val: time.Date(2024, 2, 29, 13, 0, 0, 0, time.UTC), expected: NewDate(2024, 2, 29)},
+		{name: "uuid", typ: types.BasicUUID, val: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", expected: [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf, err := EncodeAs(test.typ, test.val, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			val, _, err := Decode(test.typ, buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if val != test.expected {
+				t.Errorf("unexpected value: %#v, expected %#v", val, test.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeAsInvalid(t *testing.T) {
+	_, err := EncodeAs(types.BasicInt8, 300, nil)
+	if err == nil {
+		t.Error("expected an overflowing value to be rejected")
+	}
+
+	_, err = EncodeAs(types.BasicInt8, sql.NullInt64{Int64: 300, Valid: true}, nil)
+	if err == nil {
+		t.Error("expected an overflowing value to be rejected")
+	}
+
+	buf, err := EncodeAs(types.BasicString, []byte("lunodb"), []byte{1})
+	if err == nil || len(buf) != 1 {
+		t.Errorf("expected a mismatching value to be rejected: %x, %v", buf, err)
+	}
+}
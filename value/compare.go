@@ -0,0 +1,216 @@
+package value
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Compare compares the given values and returns -1, 0 or +1 if a is less
+// than, equal to or greater than b. Numeric values are compared by their
+// numeric value regardless of their Go type, decimals are compared exactly.
+// Strings, bytes, UUIDs, bools, temporal values and inet prefixes are
+// compared with values of the same kind. An error is returned if the values
+// can not be compared, including NULL values.
+func Compare(a any, b any) (int, error) {
+	a, b = indirect(a), indirect(b)
+	if a == nil || b == nil {
+		return 0, fmt.Errorf("cannot compare null values")
+	}
+
+	if isDecimal(a) || isDecimal(b) {
+		x, ok := toRat(a)
+		y, oky := toRat(b)
+		if ok && oky {
+			return x.Cmp(y), nil
+		}
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isNumeric(av) && isNumeric(bv):
+		return compareNumeric(av, bv), nil
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return strings.Compare(av.String(), bv.String()), nil
+	case av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool:
+		return compareBool(av.Bool(), bv.Bool()), nil
+	}
+
+	switch x := a.(type) {
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), nil
+		}
+	case Date:
+		if y, ok := b.(Date); ok {
+			return time.Time(x).Compare(time.Time(y)), nil
+		}
+	case Time:
+		if y, ok := b.(Time); ok {
+			return cmp.Compare(nanosOfDay(x), nanosOfDay(y)), nil
+		}
+	case Duration:
+		if y, ok := b.(Duration); ok {
+			return cmp.Or(cmp.Compare(x.Months, y.Months), cmp.Compare(x.Days, y.Days), cmp.Compare(x.Nanos, y.Nanos)), nil
+		}
+	case netip.Prefix:
+		if y, ok := b.(netip.Prefix); ok {
+			return cmp.Or(x.Addr().Compare(y.Addr()), cmp.Compare(x.Bits(), y.Bits())), nil
+		}
+	}
+
+	x, okx := toBytes(av)
+	y, oky := toBytes(bv)
+	if okx && oky {
+		return bytes.Compare(x, y), nil
+	}
+
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
+
+func indirect(val any) any {
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+
+		if _, ok := rv.Interface().(*big.Rat); ok {
+			return rv.Interface()
+		}
+
+		if _, ok := rv.Interface().(*big.Int); ok {
+			return rv.Interface()
+		}
+
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+
+	return rv.Interface()
+}
+
+func isNumeric(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func compareNumeric(a reflect.Value, b reflect.Value) int {
+	if a.CanFloat() || b.CanFloat() {
+		return cmp.Compare(toFloat(a), toFloat(b))
+	}
+
+	if a.CanInt() && b.CanInt() {
+		return cmp.Compare(a.Int(), b.Int())
+	}
+
+	if a.CanUint() && b.CanUint() {
+		return cmp.Compare(a.Uint(), b.Uint())
+	}
+
+	if a.CanInt() {
+		if a.Int() < 0 {
+			return -1
+		}
+
+		return cmp.Compare(uint64(a.Int()), b.Uint())
+	}
+
+	if b.Int() < 0 {
+		return 1
+	}
+
+	return cmp.Compare(a.Uint(), uint64(b.Int()))
+}
+
+func toFloat(rv reflect.Value) float64 {
+	switch {
+	case rv.CanFloat():
+		return rv.Float()
+	case rv.CanInt():
+		return float64(rv.Int())
+	default:
+		return float64(rv.Uint())
+	}
+}
+
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func nanosOfDay(t Time) int64 {
+	hour, minute, second := time.Time(t).Clock()
+	return int64(hour)*int64(time.Hour) + int64(minute)*int64(time.Minute) + int64(second)*int64(time.Second) + int64(time.Time(t).Nanosecond())
+}
+
+func isDecimal(val any) bool {
+	switch val.(type) {
+	case Decimal, big.Rat, *big.Rat, big.Int, *big.Int:
+		return true
+	}
+
+	return false
+}
+
+// toRat converts the given numeric or decimal value into a rational.
+func toRat(val any) (*big.Rat, bool) {
+	switch v := val.(type) {
+	case Decimal:
+		return v.Rat(), true
+	case big.Rat:
+		return &v, true
+	case *big.Rat:
+		return v, true
+	case big.Int:
+		return new(big.Rat).SetInt(&v), true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.CanInt():
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case rv.CanUint():
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case rv.CanFloat():
+		result, ok := new(big.Rat).SetString(fmt.Sprint(rv.Float()))
+		return result, ok
+	}
+
+	return nil, false
+}
+
+func toBytes(rv reflect.Value) ([]byte, bool) {
+	switch {
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return rv.Bytes(), true
+	case rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8:
+		result := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(result), rv)
+		return result, true
+	}
+
+	return nil, false
+}
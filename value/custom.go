@@ -0,0 +1,154 @@
+package value
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// Valuer is implemented by types which convert themselves into a value which
+// could be encoded, such as domain types wrapping a primitive.
+type Valuer interface {
+	LunoValue() (any, error)
+}
+
+// Encoder is implemented by types which encode themselves. The returned type
+// describes the appended frame.
+type Encoder interface {
+	EncodeLuno(buf []byte) (*lunopb.Type, []byte, error)
+}
+
+// Typer is implemented by custom types declaring the type of their encoded
+// values, allowing TypeOf to type them without encoding a value. LunoType is
+// called on a pointer to the zero value.
+type Typer interface {
+	LunoType() *lunopb.Type
+}
+
+// EncoderFunc encodes values of a registered type.
+type EncoderFunc func(val any, buf []byte) ([]byte, error)
+
+// registered represents an encoder registered for a type.
+type registered struct {
+	typ    *lunopb.Type
+	encode EncoderFunc
+}
+
+// encoders holds the registered encoders by type.
+var encoders sync.Map
+
+// Register registers the given encoder for values of type T encoded as the
+// given type, allowing types from third party packages to be encoded.
+// Registered encoders take precedence over the Encoder and Valuer interfaces
+// but not over the types supported by Encode directly.
+func Register[T any](typ *lunopb.Type, encoder func(val T, buf []byte) ([]byte, error)) {
+	encoders.Store(reflect.TypeFor[T](), registered{
+		typ: typ,
+		encode: func(val any, buf []byte) ([]byte, error) {
+			return encoder(val.(T), buf)
+		},
+	})
+}
+
+var (
+	typerType        = reflect.TypeFor[Typer]()
+	encoderType      = reflect.TypeFor[Encoder]()
+	valuerType       = reflect.TypeFor[Valuer]()
+	driverValuerType = reflect.TypeFor[driver.Valuer]()
+)
+
+// customTypeOf returns the type of the values of the given custom type
+// without encoding a value. Any is returned for custom types which do not
+// declare their type through Register or Typer, their type is only known once
+// a value has been encoded. False is returned if the type is not a custom
+// type.
+func customTypeOf(typ reflect.Type) (*lunopb.Type, bool) {
+	encoder, ok := encoders.Load(typ)
+	if ok {
+		return encoder.(registered).typ, true
+	}
+
+	if reflect.PointerTo(typ).Implements(typerType) {
+		return reflect.New(typ).Interface().(Typer).LunoType(), true
+	}
+
+	if implements(typ, encoderType) || implements(typ, valuerType) {
+		return types.BasicAny, true
+	}
+
+	if uuidLike(typ) {
+		return types.BasicUUID, true
+	}
+
+	if implements(typ, driverValuerType) {
+		return types.BasicAny, true
+	}
+
+	return nil, false
+}
+
+// implements returns true if the given type, or a pointer to it, implements
+// the given interface.
+func implements(typ reflect.Type, iface reflect.Type) bool {
+	return typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface)
+}
+
+// uuidLike returns true for named UUID types such as uuid.UUID.
+func uuidLike(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Array && typ.Len() == 16 && typ.Elem().Kind() == reflect.Uint8
+}
+
+// encodeCustom encodes the given value using a registered encoder or the
+// Encoder, Valuer or driver.Valuer interfaces. False is returned if the value
+// does not support any of these.
+func encodeCustom(val any, buf []byte) (_ *lunopb.Type, _ []byte, ok bool, err error) {
+	encoder, ok := encoders.Load(reflect.TypeOf(val))
+	if ok {
+		buf, err := encoder.(registered).encode(val, buf)
+		return encoder.(registered).typ, buf, true, err
+	}
+
+	switch v := val.(type) {
+	case Encoder:
+		offset := len(buf)
+		typ, buf, err := v.EncodeLuno(buf)
+		if err != nil {
+			return types.BasicAny, buf[:offset], true, err
+		}
+
+		return typ, buf, true, nil
+	case Valuer:
+		result, err := v.LunoValue()
+		if err != nil {
+			return types.BasicAny, buf, true, fmt.Errorf("%T: %w", val, err)
+		}
+
+		typ, buf, err := Encode(result, buf)
+		return typ, buf, true, err
+	}
+
+	// NOTE: named UUID types (e.g. uuid.UUID) implement driver.Valuer returning
+	// their textual representation, these are encoded as UUID instead.
+	rv := reflect.ValueOf(val)
+	if uuidLike(rv.Type()) {
+		buf, err := EncodeUUID(rv.Convert(reflect.TypeFor[[16]byte]()).Interface().([16]byte), buf)
+		return types.BasicUUID, buf, true, err
+	}
+
+	valuer, ok := val.(driver.Valuer)
+	if !ok {
+		return nil, buf, false, nil
+	}
+
+	result, err := valuer.Value()
+	if err != nil {
+		return types.BasicAny, buf, true, fmt.Errorf("%T: %w", val, err)
+	}
+
+	typ, buf, err := Encode(result, buf)
+	return typ, buf, true, err
+}
@@ -0,0 +1,223 @@
+package value
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	bigTwo  = big.NewInt(2)
+	bigFive = big.NewInt(5)
+	bigTen  = big.NewInt(10)
+)
+
+// Decimal represents an exact decimal number as an unscaled integer and a
+// scale, the represented value is Unscaled × 10^-Scale. A nil unscaled
+// integer represents zero.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+// NewDecimal constructs a new decimal of the given unscaled integer and scale.
+func NewDecimal(unscaled *big.Int, scale int32) Decimal {
+	return Decimal{Unscaled: unscaled, Scale: scale}
+}
+
+// ParseDecimal parses the given decimal string (e.g. -123.45 or 1.5e3).
+func ParseDecimal(val string) (Decimal, error) {
+	mantissa, exponent, ok := strings.Cut(strings.ToLower(val), "e")
+
+	scale := int64(0)
+	if ok {
+		exp, err := strconv.ParseInt(exponent, 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal: %q", val)
+		}
+
+		scale = -exp
+	}
+
+	integer, fraction, _ := strings.Cut(mantissa, ".")
+	digits := strings.TrimLeft(integer, "+-") + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || len(integer)-len(strings.TrimLeft(integer, "+-")) > 1 {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", val)
+	}
+
+	unscaled, _ := new(big.Int).SetString(digits, 10)
+	if strings.HasPrefix(integer, "-") {
+		unscaled.Neg(unscaled)
+	}
+
+	scale += int64(len(fraction))
+	if scale != int64(int32(scale)) {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q: scale out of range", val)
+	}
+
+	return Decimal{Unscaled: unscaled, Scale: int32(scale)}, nil
+}
+
+// DecimalFromRat converts the given rational into a decimal. An error is
+// returned if the rational can not be represented as a finite decimal (e.g.
+// 1/3).
+func DecimalFromRat(val *big.Rat) (Decimal, error) {
+	denom := new(big.Int).Set(val.Denom())
+	twos, fives := 0, 0
+
+	mod := new(big.Int)
+	for {
+		quo, rem := new(big.Int).QuoRem(denom, bigTwo, mod)
+		if rem.Sign() != 0 {
+			break
+		}
+
+		denom, twos = quo, twos+1
+	}
+
+	for {
+		quo, rem := new(big.Int).QuoRem(denom, bigFive, mod)
+		if rem.Sign() != 0 {
+			break
+		}
+
+		denom, fives = quo, fives+1
+	}
+
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return Decimal{}, fmt.Errorf("rational %s has no finite decimal representation", val)
+	}
+
+	scale := max(twos, fives)
+	unscaled := new(big.Int).Mul(val.Num(), pow10(scale))
+	unscaled.Quo(unscaled, val.Denom())
+
+	return Decimal{Unscaled: unscaled, Scale: int32(scale)}, nil
+}
+
+// Rat returns the decimal as a rational.
+func (decimal Decimal) Rat() *big.Rat {
+	result := new(big.Rat).SetInt(decimal.unscaled())
+	if decimal.Scale > 0 {
+		return result.Quo(result, new(big.Rat).SetInt(pow10(int(decimal.Scale))))
+	}
+
+	return result.Mul(result, new(big.Rat).SetInt(pow10(int(-decimal.Scale))))
+}
+
+// Rescale returns the decimal with the given scale. An error is returned if
+// rescaling would drop non-zero digits.
+func (decimal Decimal) Rescale(scale int32) (Decimal, error) {
+	unscaled := decimal.unscaled()
+	if scale >= decimal.Scale {
+		unscaled = new(big.Int).Mul(unscaled, pow10(int(scale-decimal.Scale)))
+		return Decimal{Unscaled: unscaled, Scale: scale}, nil
+	}
+
+	quo, rem := new(big.Int).QuoRem(unscaled, pow10(int(decimal.Scale-scale)), new(big.Int))
+	if rem.Sign() != 0 {
+		return decimal, fmt.Errorf("value %s exceeds scale %d", decimal, scale)
+	}
+
+	return Decimal{Unscaled: quo, Scale: scale}, nil
+}
+
+// String returns the decimal string of the decimal, preserving its scale
+// (e.g. -123.4500).
+func (decimal Decimal) String() string {
+	unscaled := decimal.unscaled()
+	digits := new(big.Int).Abs(unscaled).String()
+
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	if decimal.Scale <= 0 {
+		return sign + digits + strings.Repeat("0", int(-decimal.Scale))
+	}
+
+	scale := int(decimal.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+func (decimal Decimal) unscaled() *big.Int {
+	if decimal.Unscaled == nil {
+		return new(big.Int)
+	}
+
+	return decimal.Unscaled
+}
+
+func pow10(exp int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(exp)), nil)
+}
+
+// EncodeDecimal encodes the given decimal, rational, integer or decimal string
+// as its exact decimal string. NOTE: the LunoDB type system has no decimal
+// kind, decimals are therefore typed and encoded as strings to avoid a lossy
+// conversion to a float. The encoded value is indistinguishable from text and
+// its precision is not checked, this encoding is provisional until the API
+// carries a decimal kind with precision and scale.
+func EncodeDecimal[T Decimal | *Decimal | big.Rat | *big.Rat | big.Int | *big.Int | string](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case Decimal:
+		return EncodeString(v.String(), buf)
+	case *Decimal:
+		if v == nil {
+			return buf, nil
+		}
+
+		return EncodeString(v.String(), buf)
+	case big.Rat:
+		return encodeRat(&v, buf)
+	case *big.Rat:
+		if v == nil {
+			return buf, nil
+		}
+
+		return encodeRat(v, buf)
+	case big.Int:
+		return EncodeString(v.String(), buf)
+	case *big.Int:
+		if v == nil {
+			return buf, nil
+		}
+
+		return EncodeString(v.String(), buf)
+	case string:
+		decimal, err := ParseDecimal(v)
+		if err != nil {
+			return buf, err
+		}
+
+		return EncodeString(decimal.String(), buf)
+	default:
+		return buf, fmt.Errorf("unsupported decimal type: %T", val)
+	}
+}
+
+func encodeRat(val *big.Rat, buf []byte) ([]byte, error) {
+	decimal, err := DecimalFromRat(val)
+	if err != nil {
+		return buf, err
+	}
+
+	return EncodeString(decimal.String(), buf)
+}
+
+// DecodeDecimal decodes a decimal string from the front of the buffer.
+func DecodeDecimal(buf []byte) (Decimal, []byte, error) {
+	val, buf, err := DecodeString(buf)
+	if err != nil {
+		return Decimal{}, buf, err
+	}
+
+	decimal, err := ParseDecimal(val)
+	return decimal, buf, err
+}
@@ -0,0 +1,93 @@
+package value
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		val      string
+		unscaled int64
+		scale    int32
+		expected string
+	}{
+		{val: "123.45", unscaled: 12345, scale: 2, expected: "123.45"},
+		{val: "-123.45", unscaled: -12345, scale: 2, expected: "-123.45"},
+		{val: "+7", unscaled: 7, scale: 0, expected: "7"},
+		{val: "-0.001", unscaled: -1, scale: 3, expected: "-0.001"},
+		{val: "42", unscaled: 42, scale: 0, expected: "42"},
+		{val: "0", unscaled: 0, scale: 0, expected: "0"},
+		{val: ".5", unscaled: 5, scale: 1, expected: "0.5"},
+		{val: "1.5e3", unscaled: 15, scale: -2, expected: "1500"},
+		{val: "-1.5E-3", unscaled: -15, scale: 4, expected: "-0.0015"},
+		{val: "1.2300", unscaled: 12300, scale: 4, expected: "1.2300"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.val, func(t *testing.T) {
+			decimal, err := ParseDecimal(test.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if decimal.Unscaled.Int64() != test.unscaled || decimal.Scale != test.scale {
+				t.Errorf("unexpected decimal: %s × 10^-%d", decimal.Unscaled, decimal.Scale)
+			}
+
+			if decimal.String() != test.expected {
+				t.Errorf("unexpected string: %s, expected %s", decimal, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseDecimalInvalid(t *testing.T) {
+	for _, val := range []string{"", "-", "1.2.3", "--1", "1-", "abc", "1e", "1e99999999999", "1,5"} {
+		_, err := ParseDecimal(val)
+		if err == nil {
+			t.Errorf("expected %q to be rejected", val)
+		}
+	}
+}
+
+func TestDecimalFromRat(t *testing.T) {
+	tests := []struct {
+		val      *big.Rat
+		expected string
+	}{
+		{val: big.NewRat(1, 4), expected: "0.25"},
+		{val: big.NewRat(-1, 4), expected: "-0.25"},
+		{val: big.NewRat(1, 8), expected: "0.125"},
+		{val: big.NewRat(3, 50), expected: "0.06"},
+		{val: big.NewRat(42, 1), expected: "42"},
+		{val: big.NewRat(-42, 1), expected: "-42"},
+		{val: big.NewRat(0, 1), expected: "0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.val.String(), func(t *testing.T) {
+			decimal, err := DecimalFromRat(test.val)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if decimal.String() != test.expected {
+				t.Errorf("unexpected decimal: %s, expected %s", decimal, test.expected)
+			}
+
+			if decimal.Rat().Cmp(test.val) != 0 {
+				t.Errorf("unexpected rational: %s", decimal.Rat())
+			}
+		})
+	}
+}
+
+func TestDecimalFromRatNonTerminating(t *testing.T) {
+	for _, val := range []*big.Rat{big.NewRat(1, 3), big.NewRat(-2, 3), big.NewRat(1, 7), big.NewRat(1, 30)} {
+		_, err := DecimalFromRat(val)
+		if err == nil {
+			t.Errorf("expected %s to be rejected", val)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// ErrShortBuffer is returned when a buffer ends before a value has been fully
+// decoded.
+var ErrShortBuffer = errors.New("short buffer")
+
+// Decode decodes a single value of the given type from the front of the
+// buffer and returns the remaining bytes. An empty buffer represents NULL and
+// is decoded as nil. Values are decoded into the Go types accepted by the
+// Encode* functions, arrays are decoded as []any, objects as map[string]any,
+// tuples as Tuple and records as Record.
+func Decode(typ *lunopb.Type, buf []byte) (any, []byte, error) {
+	if len(buf) == 0 {
+		return nil, buf, nil
+	}
+
+	switch typ.GetKind() {
+	case types.Bool:
+		return decoded(DecodeBool(buf))
+	case types.String:
+		return decoded(DecodeString(buf))
+	case types.Int8:
+		return decoded(DecodeInt8(buf))
+	case types.Int16:
+		return decoded(DecodeInt16(buf))
+	case types.Int32:
+		return decoded(DecodeInt32(buf))
+	case types.Int64:
+		return decoded(DecodeInt64(buf))
+	case types.Uint8:
+		return decoded(DecodeUint8(buf))
+	case types.Uint16:
+		return decoded(DecodeUint16(buf))
+	case types.Uint32:
+		return decoded(DecodeUint32(buf))
+	case types.Uint64:
+		return decoded(DecodeUint64(buf))
+	case types.Float32:
+		return decoded(DecodeFloat32(buf))
+	case types.Float64:
+		return decoded(DecodeFloat64(buf))
+	case types.Bytes:
+		return decoded(DecodeBytes(buf))
+	case types.UUID:
+		return decoded(DecodeUUID(buf))
+	case types.Inet:
+		return decoded(DecodeInet(buf))
+	case types.Timestamp:
+		return decoded(DecodeTimestamp(buf))
+	case types.Date:
+		return decoded(DecodeDate(buf))
+	case types.Time:
+		return decoded(DecodeTime(buf))
+	case types.Duration:
+		return decoded(DecodeDuration(buf))
+	case types.Array:
+		return decoded(DecodeArray(typ.Underlying, buf))
+	case types.Object:
+		return decoded(DecodeObject(buf))
+	case types.Tuple:
+		return decoded(DecodeTuple(typ.Items, buf))
+	case types.Record:
+		return decoded(DecodeRecord(typ.Items, buf))
+	}
+
+	return nil, buf, fmt.Errorf("unable to decode value of type %s", types.Name(typ))
+}
+
+func decoded[T any](val T, buf []byte, err error) (any, []byte, error) {
+	if err != nil {
+		return nil, buf, err
+	}
+
+	return val, buf, nil
+}
+
+// decodeFrame decodes a length-prefixed (uint32) frame containing a single
+// value of the given type.
+func decodeFrame(typ *lunopb.Type, buf []byte) (any, []byte, error) {
+	frame, buf, err := take(buf, 4)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	frame, buf, err = take(buf, int(binary.BigEndian.Uint32(frame)))
+	if err != nil {
+		return nil, buf, err
+	}
+
+	val, rest, err := Decode(typ, frame)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	if len(rest) > 0 {
+		return nil, buf, fmt.Errorf("%d unexpected trailing bytes in %s frame", len(rest), types.Name(typ))
+	}
+
+	return val, buf, nil
+}
+
+// take splits the first n bytes from the given buffer.
+func take(buf []byte, n int) ([]byte, []byte, error) {
+	if n < 0 || len(buf) < n {
+		return nil, buf, ErrShortBuffer
+	}
+
+	return buf[:n], buf[n:], nil
+}
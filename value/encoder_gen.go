@@ -2,197 +2,405 @@
 package value
 
 import (
-	"fmt"
-	
+	json "encoding/json"
+	io "io"
+	big "math/big"
+	net "net"
 	netip "net/netip"
+	time "time"
+	"reflect"
 
 	"github.com/cloudproud/lunodb.go/types"
 	lunopb "github.com/cloudproud/lunodb.api/proto/types"
 )
 
-func Encode(val any, buf []byte) (_ *lunopb.Type, _ []byte, err error) {
+// knownTypes maps the types supported by Encode directly onto the type of
+// their encoded values.
+var knownTypes = map[reflect.Type]*lunopb.Type{
+	reflect.TypeFor[string](): types.BasicString,
+	reflect.TypeFor[bool](): types.BasicBool,
+	reflect.TypeFor[int8](): types.BasicInt8,
+	reflect.TypeFor[int16](): types.BasicInt16,
+	reflect.TypeFor[int32](): types.BasicInt32,
+	reflect.TypeFor[int64](): types.BasicInt64,
+	reflect.TypeFor[int](): types.BasicInt64,
+	reflect.TypeFor[uint8](): types.BasicUint8,
+	reflect.TypeFor[uint16](): types.BasicUint16,
+	reflect.TypeFor[uint32](): types.BasicUint32,
+	reflect.TypeFor[uint64](): types.BasicUint64,
+	reflect.TypeFor[float32](): types.BasicFloat32,
+	reflect.TypeFor[float64](): types.BasicFloat64,
+	reflect.TypeFor[time.Time](): types.BasicTimestamp,
+	reflect.TypeFor[Date](): types.BasicDate,
+	reflect.TypeFor[Time](): types.BasicTime,
+	reflect.TypeFor[Duration](): types.BasicDuration,
+	reflect.TypeFor[time.Duration](): types.BasicDuration,
+	reflect.TypeFor[Decimal](): types.BasicString,
+	reflect.TypeFor[big.Rat](): types.BasicString,
+	reflect.TypeFor[big.Int](): types.BasicString,
+	reflect.TypeFor[netip.Prefix](): types.BasicInet,
+	reflect.TypeFor[netip.Addr](): types.BasicInet,
+	reflect.TypeFor[net.IP](): types.BasicInet,
+	reflect.TypeFor[net.IPNet](): types.BasicInet,
+	reflect.TypeFor[[16]byte](): types.BasicUUID,
+	reflect.TypeFor[[]byte](): types.BasicBytes,
+	reflect.TypeFor[json.RawMessage](): types.BasicBytes,
+	reflect.TypeFor[io.Reader](): types.BasicBytes,
+	reflect.TypeFor[map[string]any](): types.BasicObject,
+}
+
+func Encode(val any, buf []byte) (*lunopb.Type, []byte, error) {
+	typ, buf, ok, err := encodeKnown(val, buf)
+	if ok {
+		return typ, buf, err
+	}
+
+	return encodeReflect(val, buf)
+}
+
+// encodeKnown encodes values of the types supported by Encode directly. False
+// is returned for any other type.
+func encodeKnown(val any, buf []byte) (_ *lunopb.Type, _ []byte, ok bool, err error) {
 	switch v := val.(type) {
+	case nil:
+		return types.BasicAny, buf, true, nil
 	case string:
 		buf, err = EncodeString(v, buf)
-		return types.BasicString, buf, err
+		return types.BasicString, buf, true, err
 	case bool:
 		buf, err = EncodeBool(v, buf)
-		return types.BasicBool, buf, err
+		return types.BasicBool, buf, true, err
 	case int8:
 		buf, err = EncodeInt8(v, buf)
-		return types.BasicInt8, buf, err
+		return types.BasicInt8, buf, true, err
 	case int16:
 		buf, err = EncodeInt16(v, buf)
-		return types.BasicInt16, buf, err
+		return types.BasicInt16, buf, true, err
 	case int32:
 		buf, err = EncodeInt32(v, buf)
-		return types.BasicInt32, buf, err
+		return types.BasicInt32, buf, true, err
 	case int64:
 		buf, err = EncodeInt64(v, buf)
-		return types.BasicInt64, buf, err
+		return types.BasicInt64, buf, true, err
 	case int:
 		buf, err = EncodeInt64(v, buf)
-		return types.BasicInt64, buf, err
+		return types.BasicInt64, buf, true, err
 	case uint8:
 		buf, err = EncodeUint8(v, buf)
-		return types.BasicUint8, buf, err
+		return types.BasicUint8, buf, true, err
 	case uint16:
 		buf, err = EncodeUint16(v, buf)
-		return types.BasicUint16, buf, err
+		return types.BasicUint16, buf, true, err
 	case uint32:
 		buf, err = EncodeUint32(v, buf)
-		return types.BasicUint32, buf, err
+		return types.BasicUint32, buf, true, err
 	case uint64:
 		buf, err = EncodeUint64(v, buf)
-		return types.BasicUint64, buf, err
+		return types.BasicUint64, buf, true, err
 	case float32:
 		buf, err = EncodeFloat32(v, buf)
-		return types.BasicFloat32, buf, err
+		return types.BasicFloat32, buf, true, err
 	case float64:
 		buf, err = EncodeFloat64(v, buf)
-		return types.BasicFloat64, buf, err
+		return types.BasicFloat64, buf, true, err
+	case time.Time:
+		buf, err = EncodeTimestamp(v, buf)
+		return types.BasicTimestamp, buf, true, err
+	case Date:
+		buf, err = EncodeDate(v, buf)
+		return types.BasicDate, buf, true, err
+	case Time:
+		buf, err = EncodeTime(v, buf)
+		return types.BasicTime, buf, true, err
+	case Duration:
+		buf, err = EncodeDuration(v, buf)
+		return types.BasicDuration, buf, true, err
+	case time.Duration:
+		buf, err = EncodeDuration(v, buf)
+		return types.BasicDuration, buf, true, err
+	case Decimal:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
+	case big.Rat:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
+	case big.Int:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
 	case netip.Prefix:
 		buf, err = EncodeInet(v, buf)
-		return types.BasicInet, buf, err
+		return types.BasicInet, buf, true, err
+	case netip.Addr:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
+	case net.IP:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
+	case net.IPNet:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
 	case [16]byte:
 		buf, err = EncodeUUID(v, buf)
-		return types.BasicUUID, buf, err
+		return types.BasicUUID, buf, true, err
+	case []byte:
+		buf, err = EncodeBytes(v, buf)
+		return types.BasicBytes, buf, true, err
+	case json.RawMessage:
+		buf, err = EncodeBytes(v, buf)
+		return types.BasicBytes, buf, true, err
+	case io.Reader:
+		buf, err = EncodeReader(v, buf)
+		return types.BasicBytes, buf, true, err
 	case map[string]any:
 		buf, err = EncodeObject(v, buf)
-		return types.BasicObject, buf, err
+		return types.BasicObject, buf, true, err
 	case *string:
 		buf, err = EncodeString(v, buf)
-		return types.BasicString, buf, err
+		return types.BasicString, buf, true, err
 	case *bool:
 		buf, err = EncodeBool(v, buf)
-		return types.BasicBool, buf, err
+		return types.BasicBool, buf, true, err
 	case *int8:
 		buf, err = EncodeInt8(v, buf)
-		return types.BasicInt8, buf, err
+		return types.BasicInt8, buf, true, err
 	case *int16:
 		buf, err = EncodeInt16(v, buf)
-		return types.BasicInt16, buf, err
+		return types.BasicInt16, buf, true, err
 	case *int32:
 		buf, err = EncodeInt32(v, buf)
-		return types.BasicInt32, buf, err
+		return types.BasicInt32, buf, true, err
 	case *int64:
 		buf, err = EncodeInt64(v, buf)
-		return types.BasicInt64, buf, err
+		return types.BasicInt64, buf, true, err
 	case *int:
 		buf, err = EncodeInt64(v, buf)
-		return types.BasicInt64, buf, err
+		return types.BasicInt64, buf, true, err
 	case *uint8:
 		buf, err = EncodeUint8(v, buf)
-		return types.BasicUint8, buf, err
+		return types.BasicUint8, buf, true, err
 	case *uint16:
 		buf, err = EncodeUint16(v, buf)
-		return types.BasicUint16, buf, err
+		return types.BasicUint16, buf, true, err
 	case *uint32:
 		buf, err = EncodeUint32(v, buf)
-		return types.BasicUint32, buf, err
+		return types.BasicUint32, buf, true, err
 	case *uint64:
 		buf, err = EncodeUint64(v, buf)
-		return types.BasicUint64, buf, err
+		return types.BasicUint64, buf, true, err
 	case *float32:
 		buf, err = EncodeFloat32(v, buf)
-		return types.BasicFloat32, buf, err
+		return types.BasicFloat32, buf, true, err
 	case *float64:
 		buf, err = EncodeFloat64(v, buf)
-		return types.BasicFloat64, buf, err
+		return types.BasicFloat64, buf, true, err
+	case *time.Time:
+		buf, err = EncodeTimestamp(v, buf)
+		return types.BasicTimestamp, buf, true, err
+	case *Date:
+		buf, err = EncodeDate(v, buf)
+		return types.BasicDate, buf, true, err
+	case *Time:
+		buf, err = EncodeTime(v, buf)
+		return types.BasicTime, buf, true, err
+	case *Duration:
+		buf, err = EncodeDuration(v, buf)
+		return types.BasicDuration, buf, true, err
+	case *time.Duration:
+		buf, err = EncodeDuration(v, buf)
+		return types.BasicDuration, buf, true, err
+	case *Decimal:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
+	case *big.Rat:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
+	case *big.Int:
+		buf, err = EncodeDecimal(v, buf)
+		return types.BasicString, buf, true, err
 	case *netip.Prefix:
 		buf, err = EncodeInet(v, buf)
-		return types.BasicInet, buf, err
+		return types.BasicInet, buf, true, err
+	case *netip.Addr:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
+	case *net.IP:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
+	case *net.IPNet:
+		buf, err = EncodeInet(v, buf)
+		return types.BasicInet, buf, true, err
+	case *[]byte:
+		buf, err = EncodeBytes(v, buf)
+		return types.BasicBytes, buf, true, err
+	case *json.RawMessage:
+		buf, err = EncodeBytes(v, buf)
+		return types.BasicBytes, buf, true, err
 	case []string:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicString), buf, err
+		return types.NewArray(types.BasicString), buf, true, err
 	case []bool:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicBool), buf, err
+		return types.NewArray(types.BasicBool), buf, true, err
 	case []int8:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt8), buf, err
+		return types.NewArray(types.BasicInt8), buf, true, err
 	case []int16:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt16), buf, err
+		return types.NewArray(types.BasicInt16), buf, true, err
 	case []int32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt32), buf, err
+		return types.NewArray(types.BasicInt32), buf, true, err
 	case []int64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt64), buf, err
+		return types.NewArray(types.BasicInt64), buf, true, err
 	case []int:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt64), buf, err
-	case []uint8:
-		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint8), buf, err
+		return types.NewArray(types.BasicInt64), buf, true, err
 	case []uint16:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint16), buf, err
+		return types.NewArray(types.BasicUint16), buf, true, err
 	case []uint32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint32), buf, err
+		return types.NewArray(types.BasicUint32), buf, true, err
 	case []uint64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint64), buf, err
+		return types.NewArray(types.BasicUint64), buf, true, err
 	case []float32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicFloat32), buf, err
+		return types.NewArray(types.BasicFloat32), buf, true, err
 	case []float64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicFloat64), buf, err
+		return types.NewArray(types.BasicFloat64), buf, true, err
+	case []time.Time:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicTimestamp), buf, true, err
+	case []Date:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDate), buf, true, err
+	case []Time:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicTime), buf, true, err
+	case []Duration:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDuration), buf, true, err
+	case []time.Duration:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDuration), buf, true, err
+	case []Decimal:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
+	case []big.Rat:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
+	case []big.Int:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
 	case []netip.Prefix:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInet), buf, err
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []netip.Addr:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []net.IP:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []net.IPNet:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
 	case [][16]byte:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUUID), buf, err
+		return types.NewArray(types.BasicUUID), buf, true, err
+	case [][]byte:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicBytes), buf, true, err
+	case []json.RawMessage:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicBytes), buf, true, err
 	case []map[string]any:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicObject), buf, err
+		return types.NewArray(types.BasicObject), buf, true, err
 	case []*string:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicString), buf, err
+		return types.NewArray(types.BasicString), buf, true, err
 	case []*bool:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicBool), buf, err
+		return types.NewArray(types.BasicBool), buf, true, err
 	case []*int8:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt8), buf, err
+		return types.NewArray(types.BasicInt8), buf, true, err
 	case []*int16:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt16), buf, err
+		return types.NewArray(types.BasicInt16), buf, true, err
 	case []*int32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt32), buf, err
+		return types.NewArray(types.BasicInt32), buf, true, err
 	case []*int64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt64), buf, err
+		return types.NewArray(types.BasicInt64), buf, true, err
 	case []*int:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInt64), buf, err
+		return types.NewArray(types.BasicInt64), buf, true, err
 	case []*uint8:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint8), buf, err
+		return types.NewArray(types.BasicUint8), buf, true, err
 	case []*uint16:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint16), buf, err
+		return types.NewArray(types.BasicUint16), buf, true, err
 	case []*uint32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint32), buf, err
+		return types.NewArray(types.BasicUint32), buf, true, err
 	case []*uint64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicUint64), buf, err
+		return types.NewArray(types.BasicUint64), buf, true, err
 	case []*float32:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicFloat32), buf, err
+		return types.NewArray(types.BasicFloat32), buf, true, err
 	case []*float64:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicFloat64), buf, err
+		return types.NewArray(types.BasicFloat64), buf, true, err
+	case []*time.Time:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicTimestamp), buf, true, err
+	case []*Date:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDate), buf, true, err
+	case []*Time:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicTime), buf, true, err
+	case []*Duration:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDuration), buf, true, err
+	case []*time.Duration:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicDuration), buf, true, err
+	case []*Decimal:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
+	case []*big.Rat:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
+	case []*big.Int:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicString), buf, true, err
 	case []*netip.Prefix:
 		buf, err = EncodeArray(v, buf)
-		return types.NewArray(types.BasicInet), buf, err
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []*netip.Addr:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []*net.IP:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []*net.IPNet:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicInet), buf, true, err
+	case []*[]byte:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicBytes), buf, true, err
+	case []*json.RawMessage:
+		buf, err = EncodeArray(v, buf)
+		return types.NewArray(types.BasicBytes), buf, true, err
 	}
 
-	return types.BasicAny, buf, fmt.Errorf("unsupported type: %T", val)
+	return nil, buf, false, nil
 }
@@ -35,3 +35,23 @@ func EncodeFloat64[T float64 | *float64](val T, buf []byte) ([]byte, error) {
 		return buf, fmt.Errorf("unsupported float64 type: %T", val)
 	}
 }
+
+// DecodeFloat32 decodes a float32 from the front of the buffer.
+func DecodeFloat32(buf []byte) (float32, []byte, error) {
+	val, buf, err := take(buf, 4)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return math.Float32frombits(binary.BigEndian.Uint32(val)), buf, nil
+}
+
+// DecodeFloat64 decodes a float64 from the front of the buffer.
+func DecodeFloat64(buf []byte) (float64, []byte, error) {
+	val, buf, err := take(buf, 8)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return math.Float64frombits(binary.BigEndian.Uint64(val)), buf, nil
+}
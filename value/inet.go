@@ -2,10 +2,15 @@ package value
 
 import (
 	"fmt"
+	"net"
 	"net/netip"
+	"strings"
 )
 
-func EncodeInet[T netip.Prefix | *netip.Prefix](val T, buf []byte) ([]byte, error) {
+// EncodeInet encodes the given address or prefix as an inet. Host addresses
+// are normalized to a /32 (IPv4) or /128 (IPv6) prefix, IPv4-mapped IPv6
+// addresses held by a net.IP are encoded as IPv4.
+func EncodeInet[T netip.Prefix | *netip.Prefix | netip.Addr | *netip.Addr | net.IP | *net.IP | net.IPNet | *net.IPNet](val T, buf []byte) ([]byte, error) {
 	switch v := any(val).(type) {
 	case netip.Prefix:
 		return v.AppendBinary(buf)
@@ -15,7 +20,101 @@ func EncodeInet[T netip.Prefix | *netip.Prefix](val T, buf []byte) ([]byte, erro
 		}
 
 		return v.AppendBinary(buf)
+	case netip.Addr:
+		return appendAddr(v, buf)
+	case *netip.Addr:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendAddr(*v, buf)
+	case net.IP:
+		return appendIP(v, buf)
+	case *net.IP:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendIP(*v, buf)
+	case net.IPNet:
+		return appendIPNet(v, buf)
+	case *net.IPNet:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendIPNet(*v, buf)
 	default:
 		return buf, fmt.Errorf("unsupported inet type: %T", val)
 	}
 }
+
+func appendAddr(addr netip.Addr, buf []byte) ([]byte, error) {
+	if !addr.IsValid() {
+		return buf, fmt.Errorf("invalid inet address: %s", addr)
+	}
+
+	addr = addr.WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()).AppendBinary(buf)
+}
+
+func appendIP(ip net.IP, buf []byte) ([]byte, error) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return buf, fmt.Errorf("invalid inet address: %s", ip)
+	}
+
+	return appendAddr(addr.Unmap(), buf)
+}
+
+func appendIPNet(ipnet net.IPNet, buf []byte) ([]byte, error) {
+	addr, ok := netip.AddrFromSlice(ipnet.IP)
+	if !ok {
+		return buf, fmt.Errorf("invalid inet network: %s", ipnet.String())
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if bits == 0 {
+		return buf, fmt.Errorf("invalid inet network mask: %s", ipnet.Mask)
+	}
+
+	addr = addr.Unmap()
+	if bits > addr.BitLen() {
+		ones -= bits - addr.BitLen()
+	}
+
+	prefix := netip.PrefixFrom(addr, ones)
+	if !prefix.IsValid() {
+		return buf, fmt.Errorf("invalid inet network: %s", ipnet.String())
+	}
+
+	return prefix.AppendBinary(buf)
+}
+
+// ParseInet parses the given address (e.g. 192.168.0.1) or prefix (e.g.
+// 10.0.0.0/8). Addresses are normalized to a /32 (IPv4) or /128 (IPv6)
+// prefix.
+func ParseInet(val string) (netip.Prefix, error) {
+	if strings.Contains(val, "/") {
+		return netip.ParsePrefix(val)
+	}
+
+	addr, err := netip.ParseAddr(val)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	addr = addr.WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// DecodeInet decodes an inet prefix. The address length is derived from the
+// frame length, the entire buffer is therefore consumed.
+func DecodeInet(buf []byte) (prefix netip.Prefix, _ []byte, err error) {
+	err = prefix.UnmarshalBinary(buf)
+	if err != nil {
+		return prefix, buf, err
+	}
+
+	return prefix, nil, nil
+}
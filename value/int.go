@@ -123,3 +123,71 @@ func EncodeUint64[T uint64 | *uint64](val T, buf []byte) ([]byte, error) {
 		return buf, fmt.Errorf("unsupported uint64 type: %T", val)
 	}
 }
+
+// DecodeInt8 decodes an int8 from the front of the buffer.
+func DecodeInt8(buf []byte) (int8, []byte, error) {
+	val, buf, err := take(buf, 1)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return int8(val[0]), buf, nil
+}
+
+// DecodeInt16 decodes an int16 from the front of the buffer.
+func DecodeInt16(buf []byte) (int16, []byte, error) {
+	val, buf, err := DecodeUint16(buf)
+	return int16(val), buf, err
+}
+
+// DecodeInt32 decodes an int32 from the front of the buffer.
+func DecodeInt32(buf []byte) (int32, []byte, error) {
+	val, buf, err := DecodeUint32(buf)
+	return int32(val), buf, err
+}
+
+// DecodeInt64 decodes an int64 from the front of the buffer.
+func DecodeInt64(buf []byte) (int64, []byte, error) {
+	val, buf, err := DecodeUint64(buf)
+	return int64(val), buf, err
+}
+
+// DecodeUint8 decodes an uint8 from the front of the buffer.
+func DecodeUint8(buf []byte) (uint8, []byte, error) {
+	val, buf, err := take(buf, 1)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return val[0], buf, nil
+}
+
+// DecodeUint16 decodes an uint16 from the front of the buffer.
+func DecodeUint16(buf []byte) (uint16, []byte, error) {
+	val, buf, err := take(buf, 2)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return binary.BigEndian.Uint16(val), buf, nil
+}
+
+// DecodeUint32 decodes an uint32 from the front of the buffer.
+func DecodeUint32(buf []byte) (uint32, []byte, error) {
+	val, buf, err := take(buf, 4)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return binary.BigEndian.Uint32(val), buf, nil
+}
+
+// DecodeUint64 decodes an uint64 from the front of the buffer.
+func DecodeUint64(buf []byte) (uint64, []byte, error) {
+	val, buf, err := take(buf, 8)
+	if err != nil {
+		return 0, buf, err
+	}
+
+	return binary.BigEndian.Uint64(val), buf, nil
+}
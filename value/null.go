@@ -0,0 +1,32 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+)
+
+// IsNull returns true if the given value represents NULL. Untyped nil values
+// and nil pointers are considered NULL and encoded as an empty frame.
+func IsNull(val any) bool {
+	if val == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(val)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// NullableOf returns the type of the value held by the given database/sql
+// nullable type, such as sql.NullString or sql.Null[T]. False is returned for
+// any other type.
+func NullableOf(typ reflect.Type) (reflect.Type, bool) {
+	if typ.PkgPath() != "database/sql" || !strings.HasPrefix(typ.Name(), "Null") {
+		return nil, false
+	}
+
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 || typ.Field(1).Name != "Valid" {
+		return nil, false
+	}
+
+	return typ.Field(0).Type, true
+}
@@ -2,31 +2,126 @@ package value
 
 import (
 	"encoding/binary"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	lunopb "github.com/cloudproud/lunodb.api/proto/types"
 	"github.com/gogo/protobuf/proto"
 )
 
-func EncodeObject(val map[string]any, buf []byte) (frame []byte, err error) {
-	var typed *lunopb.Type
-	for k, v := range val {
-		buf = append(buf, []byte(k)...)
-		buf = append(buf, 0)
+// EncodeObject encodes the given map as an object. Objects are encoded as the
+// number of members (uint32) followed by every member in sorted key order.
+// Members are encoded as a length-prefixed (uint32) key, a length-prefixed
+// (uint32) protobuf encoded type descriptor and a length-prefixed (uint32)
+// value frame. NULL members are encoded as an empty value frame.
+func EncodeObject[T any](val map[string]T, buf []byte) (_ []byte, err error) {
+	keys := make([]string, 0, len(val))
+	for key := range val {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
 
-		typed, frame, err = Encode(v, nil)
+	offset := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(keys)))
+	for _, key := range keys {
+		buf, err = encodeMember(key, val[key], buf)
 		if err != nil {
-			return nil, err
+			return buf[:offset], err
 		}
+	}
+
+	return buf, nil
+}
+
+// encodeMap encodes the given reflected map with string keys as an object.
+func encodeMap(rv reflect.Value, buf []byte) (_ []byte, err error) {
+	keys := rv.MapKeys()
+	slices.SortFunc(keys, func(a reflect.Value, b reflect.Value) int {
+		return strings.Compare(a.String(), b.String())
+	})
 
-		header, err := proto.Marshal(typed)
+	offset := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(keys)))
+	for _, key := range keys {
+		buf, err = encodeMember(key.String(), rv.MapIndex(key).Interface(), buf)
 		if err != nil {
-			return nil, err
+			return buf[:offset], err
 		}
-
-		buf = binary.BigEndian.AppendUint32(buf, uint32(len(header)))
-		buf = binary.BigEndian.AppendUint32(buf, uint32(len(frame)))
-		buf = append(buf, frame...)
 	}
 
 	return buf, nil
 }
+
+func encodeMember(key string, val any, buf []byte) ([]byte, error) {
+	typ, frame, err := Encode(val, nil)
+	if err != nil {
+		return buf, fmt.Errorf("object member %q: %w", key, err)
+	}
+
+	descriptor, err := proto.Marshal(typ)
+	if err != nil {
+		return buf, fmt.Errorf("object member %q: %w", key, err)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(key)))
+	buf = append(buf, key...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(descriptor)))
+	buf = append(buf, descriptor...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(frame)))
+	return append(buf, frame...), nil
+}
+
+// DecodeObject decodes an object from the front of the buffer. Members are
+// decoded using their embedded type descriptors, NULL members are decoded as
+// nil.
+func DecodeObject(buf []byte) (map[string]any, []byte, error) {
+	count, buf, err := DecodeUint32(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	if uint64(count)*12 > uint64(len(buf)) {
+		return nil, buf, ErrShortBuffer
+	}
+
+	result := make(map[string]any, count)
+	for range count {
+		var key, descriptor []byte
+		key, buf, err = decodeChunk(buf)
+		if err != nil {
+			return nil, buf, err
+		}
+
+		descriptor, buf, err = decodeChunk(buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("object member %q: %w", key, err)
+		}
+
+		typ := &lunopb.Type{}
+		err = proto.Unmarshal(descriptor, typ)
+		if err != nil {
+			return nil, buf, fmt.Errorf("object member %q: %w", key, err)
+		}
+
+		result[string(key)], buf, err = decodeFrame(typ, buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("object member %q: %w", key, err)
+		}
+	}
+
+	return result, buf, nil
+}
+
+// decodeChunk splits a length-prefixed (uint32) chunk from the front of the
+// buffer.
+func decodeChunk(buf []byte) ([]byte, []byte, error) {
+	size, buf, err := DecodeUint32(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	return take(buf, int(size))
+}
@@ -0,0 +1,119 @@
+package value
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+var readerType = reflect.TypeFor[io.Reader]()
+
+// TypeOf returns the type of the values encoded for the given Go type. The
+// type is derived from the Go type alone, no values are encoded. Any is
+// returned for interface types, types which can not be encoded and custom
+// types which do not declare their type through Register or Typer.
+func TypeOf(typ reflect.Type) *lunopb.Type {
+	return typeOf(typ, nil)
+}
+
+// typeOf derives the type of the given Go type. Structs encoded as records
+// and slices are derived from their field and element types. Types recurring
+// within themselves, such as linked nodes, are typed as any where they recur.
+func typeOf(typ reflect.Type, visiting []reflect.Type) *lunopb.Type {
+	result, ok := knownTypes[typ]
+	if ok {
+		return result
+	}
+
+	if typ.Kind() != reflect.Interface && typ.Implements(readerType) {
+		return types.BasicBytes
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return types.BasicAny
+	case reflect.Pointer:
+		return typeOf(typ.Elem(), visiting)
+	}
+
+	if elem, ok := NullableOf(typ); ok {
+		return typeOf(elem, visiting)
+	}
+
+	result, ok = customTypeOf(typ)
+	if ok {
+		return result
+	}
+
+	if slices.Contains(visiting, typ) {
+		return types.BasicAny
+	}
+
+	visiting = append(visiting, typ)
+
+	switch {
+	case typ == reflect.TypeFor[Tuple]():
+		return types.BasicTuple
+	case typ == reflect.TypeFor[Record]():
+		return types.BasicRecord
+	case typ.Kind() == reflect.Struct:
+		fields := StructFields(typ)
+		items := make([]*lunopb.Type, len(fields))
+		for index, field := range fields {
+			items[index] = typeOf(field.Field.Type, visiting)
+		}
+
+		return types.NewRecord(items...)
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		return types.NewArray(typeOf(typ.Elem(), visiting))
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String:
+		return types.BasicObject
+	}
+
+	return types.BasicAny
+}
+
+// encodeReflect encodes values which are not directly supported by Encode,
+// such as custom types, tuples, records, nested slices, structs and maps with
+// string keys, by reflecting over the value.
+func encodeReflect(val any, buf []byte) (*lunopb.Type, []byte, error) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		typ := TypeOf(rv.Type().Elem())
+		return typ, buf, nil
+	}
+
+	typ, buf, ok, err := encodeCustom(val, buf)
+	if ok {
+		return typ, buf, err
+	}
+
+	switch v := val.(type) {
+	case Tuple:
+		return EncodeTuple(v, buf)
+	case Record:
+		return EncodeRecord(v, buf)
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		return Encode(rv.Elem().Interface(), buf)
+	case reflect.Slice, reflect.Array:
+		return encodeSlice(rv, buf)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		buf, err := encodeMap(rv, buf)
+		return types.BasicObject, buf, err
+	case reflect.Struct:
+		return encodeStruct(rv, buf)
+	}
+
+	return types.BasicAny, buf, fmt.Errorf("unsupported type: %T", val)
+}
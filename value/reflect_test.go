@@ -0,0 +1,113 @@
+package value
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+type node struct {
+	Name     string
+	Next     *node
+	Children []node
+}
+
+func TestEncodeRecursiveStruct(t *testing.T) {
+	val := node{Name: "root", Next: &node{Name: "next"}}
+
+	typ, buf, err := Encode(val, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, _, err := Decode(typ, buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	record, ok := result.(Record)
+	if !ok || len(record) != 3 || record[0].Value != "root" {
+		t.Errorf("unexpected value: %#v", result)
+	}
+}
+
+func TestTypeOfRecursiveStruct(t *testing.T) {
+	typ := TypeOf(reflect.TypeFor[*node]())
+	if typ.Kind != types.Record || len(typ.Items) != 3 {
+		t.Fatalf("unexpected type: %s", types.Name(typ))
+	}
+
+	if typ.Items[1].Kind != types.Any {
+		t.Errorf("unexpected recurring type: %s", types.Name(typ.Items[1]))
+	}
+}
+
+// account panics when its zero value is encoded.
+type account struct {
+	balance *int64
+}
+
+func (account account) LunoValue() (any, error) {
+	return *account.balance, nil
+}
+
+// celsius declares its type through Typer.
+type celsius struct {
+	degrees float64
+}
+
+func (celsius *celsius) LunoType() *lunopb.Type {
+	return types.BasicFloat64
+}
+
+func (celsius celsius) LunoValue() (any, error) {
+	return celsius.degrees, nil
+}
+
+// fahrenheit is encoded through a registered encoder.
+type fahrenheit float64
+
+func TestTypeOfCustomTypes(t *testing.T) {
+	Register(types.BasicFloat64, func(val fahrenheit, buf []byte) ([]byte, error) {
+		return EncodeFloat64(float64(val), buf)
+	})
+
+	tests := []struct {
+		typ      reflect.Type
+		expected *lunopb.Type
+	}{
+		{typ: reflect.TypeFor[account](), expected: types.BasicAny},
+		{typ: reflect.TypeFor[*account](), expected: types.BasicAny},
+		{typ: reflect.TypeFor[celsius](), expected: types.BasicFloat64},
+		{typ: reflect.TypeFor[[]celsius](), expected: types.NewArray(types.BasicFloat64)},
+		{typ: reflect.TypeFor[fahrenheit](), expected: types.BasicFloat64},
+		{typ: reflect.TypeFor[*bytes.Buffer](), expected: types.BasicBytes},
+		{typ: reflect.TypeFor[io.Reader](), expected: types.BasicBytes},
+		{typ: reflect.TypeFor[Tuple](), expected: types.BasicTuple},
+		{typ: reflect.TypeFor[map[string]int](), expected: types.BasicObject},
+		{typ: reflect.TypeFor[chan int](), expected: types.BasicAny},
+	}
+
+	for _, test := range tests {
+		t.Run(test.typ.String(), func(t *testing.T) {
+			typ := TypeOf(test.typ)
+			if types.Name(typ) != types.Name(test.expected) {
+				t.Errorf("unexpected type: %s, expected %s", types.Name(typ), types.Name(test.expected))
+			}
+		})
+	}
+
+	typ, buf, err := Encode(fahrenheit(451), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	val, _, err := Decode(typ, buf)
+	if err != nil || val != float64(451) {
+		t.Errorf("unexpected value: %v, %v", val, err)
+	}
+}
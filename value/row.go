@@ -0,0 +1,238 @@
+package value
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// maxPooledArena is the maximum arena capacity retained when a row encoder is
+// released, preventing a single large row from pinning memory in the pool.
+const maxPooledArena = 64 << 10
+
+var rowEncoders = sync.Pool{
+	New: func() any {
+		return &RowEncoder{}
+	},
+}
+
+// RowEncoder encodes the values of a single row into a reusable arena. The
+// typed Append methods encode values without boxing them into an interface or
+// going through the type switch of Encode. A row encoder could be reused for
+// multiple rows by calling Reset in between, it is not safe for concurrent
+// use.
+type RowEncoder struct {
+	arena []byte
+	ends  []int
+	types []*lunopb.Type
+}
+
+// NewRowEncoder returns an empty row encoder from the pool. Release should be
+// called once the encoder is no longer used.
+func NewRowEncoder() *RowEncoder {
+	return rowEncoders.Get().(*RowEncoder)
+}
+
+// Release resets the encoder and returns it to the pool. The encoder and its
+// frames should not be used after it has been released.
+func (encoder *RowEncoder) Release() {
+	if cap(encoder.arena) > maxPooledArena {
+		encoder.arena = nil
+	}
+
+	encoder.Reset()
+	rowEncoders.Put(encoder)
+}
+
+// Reset removes all encoded values while retaining the allocated arena.
+func (encoder *RowEncoder) Reset() {
+	encoder.arena = encoder.arena[:0]
+	encoder.ends = encoder.ends[:0]
+	encoder.types = encoder.types[:0]
+}
+
+// Len returns the number of encoded values.
+func (encoder *RowEncoder) Len() int {
+	return len(encoder.ends)
+}
+
+// Frame returns the encoded frame of the value at the given index. NULL values
+// are returned as an empty frame. The frame is only valid until the encoder
+// is reset or released.
+func (encoder *RowEncoder) Frame(index int) []byte {
+	start := 0
+	if index > 0 {
+		start = encoder.ends[index-1]
+	}
+
+	return encoder.arena[start:encoder.ends[index]:encoder.ends[index]]
+}
+
+// Type returns the type of the value at the given index. NULL values appended
+// through AppendNull are typed as Any.
+func (encoder *RowEncoder) Type(index int) *lunopb.Type {
+	return encoder.types[index]
+}
+
+// Bytes returns all encoded frames concatenated in order.
+func (encoder *RowEncoder) Bytes() []byte {
+	return encoder.arena
+}
+
+// Values decodes all encoded values.
+func (encoder *RowEncoder) Values() ([]any, error) {
+	values := make([]any, encoder.Len())
+	for index := range values {
+		val, _, err := Decode(encoder.types[index], encoder.Frame(index))
+		if err != nil {
+			return nil, err
+		}
+
+		values[index] = val
+	}
+
+	return values, nil
+}
+
+func (encoder *RowEncoder) end(typ *lunopb.Type) {
+	encoder.ends = append(encoder.ends, len(encoder.arena))
+	encoder.types = append(encoder.types, typ)
+}
+
+// Append encodes the given value through Encode.
+func (encoder *RowEncoder) Append(val any) error {
+	offset := len(encoder.arena)
+
+	typ, arena, err := Encode(val, encoder.arena)
+	if err != nil {
+		encoder.arena = arena[:offset]
+		return err
+	}
+
+	encoder.arena = arena
+	encoder.end(typ)
+	return nil
+}
+
+// AppendNull appends a NULL value.
+func (encoder *RowEncoder) AppendNull() {
+	encoder.end(types.BasicAny)
+}
+
+// AppendBool appends a bool value.
+func (encoder *RowEncoder) AppendBool(val bool) {
+	b := byte(0)
+	if val {
+		b = 1
+	}
+
+	encoder.arena = append(encoder.arena, b)
+	encoder.end(types.BasicBool)
+}
+
+// AppendString appends a string value.
+func (encoder *RowEncoder) AppendString(val string) {
+	encoder.arena = binary.BigEndian.AppendUint64(encoder.arena, uint64(len(val)))
+	encoder.arena = append(encoder.arena, val...)
+	encoder.end(types.BasicString)
+}
+
+// AppendBytes appends a bytes value.
+func (encoder *RowEncoder) AppendBytes(val []byte) {
+	encoder.arena = appendBytes(val, encoder.arena)
+	encoder.end(types.BasicBytes)
+}
+
+// AppendInt8 appends an int8 value.
+func (encoder *RowEncoder) AppendInt8(val int8) {
+	encoder.arena = append(encoder.arena, byte(val))
+	encoder.end(types.BasicInt8)
+}
+
+// AppendInt16 appends an int16 value.
+func (encoder *RowEncoder) AppendInt16(val int16) {
+	encoder.arena = binary.BigEndian.AppendUint16(encoder.arena, uint16(val))
+	encoder.end(types.BasicInt16)
+}
+
+// AppendInt32 appends an int32 value.
+func (encoder *RowEncoder) AppendInt32(val int32) {
+	encoder.arena = binary.BigEndian.AppendUint32(encoder.arena, uint32(val))
+	encoder.end(types.BasicInt32)
+}
+
+// AppendInt64 appends an int64 value.
+func (encoder *RowEncoder) AppendInt64(val int64) {
+	encoder.arena = binary.BigEndian.AppendUint64(encoder.arena, uint64(val))
+	encoder.end(types.BasicInt64)
+}
+
+// AppendUint8 appends an uint8 value.
+func (encoder *RowEncoder) AppendUint8(val uint8) {
+	encoder.arena = append(encoder.arena, val)
+	encoder.end(types.BasicUint8)
+}
+
+// AppendUint16 appends an uint16 value.
+func (encoder *RowEncoder) AppendUint16(val uint16) {
+	encoder.arena = binary.BigEndian.AppendUint16(encoder.arena, val)
+	encoder.end(types.BasicUint16)
+}
+
+// AppendUint32 appends an uint32 value.
+func (encoder *RowEncoder) AppendUint32(val uint32) {
+	encoder.arena = binary.BigEndian.AppendUint32(encoder.arena, val)
+	encoder.end(types.BasicUint32)
+}
+
+// AppendUint64 appends an uint64 value.
+func (encoder *RowEncoder) AppendUint64(val uint64) {
+	encoder.arena = binary.BigEndian.AppendUint64(encoder.arena, val)
+	encoder.end(types.BasicUint64)
+}
+
+// AppendFloat32 appends a float32 value.
+func (encoder *RowEncoder) AppendFloat32(val float32) {
+	encoder.arena = binary.BigEndian.AppendUint32(encoder.arena, math.Float32bits(val))
+	encoder.end(types.BasicFloat32)
+}
+
+// AppendFloat64 appends a float64 value.
+func (encoder *RowEncoder) AppendFloat64(val float64) {
+	encoder.arena = binary.BigEndian.AppendUint64(encoder.arena, math.Float64bits(val))
+	encoder.end(types.BasicFloat64)
+}
+
+// AppendUUID appends an UUID value.
+func (encoder *RowEncoder) AppendUUID(val [16]byte) {
+	encoder.arena = append(encoder.arena, val[:]...)
+	encoder.end(types.BasicUUID)
+}
+
+// AppendTimestamp appends a timestamp value.
+func (encoder *RowEncoder) AppendTimestamp(val time.Time) {
+	encoder.arena = appendTimestamp(val, encoder.arena)
+	encoder.end(types.BasicTimestamp)
+}
+
+// AppendDate appends a date value.
+func (encoder *RowEncoder) AppendDate(val Date) {
+	encoder.arena = appendDate(val, encoder.arena)
+	encoder.end(types.BasicDate)
+}
+
+// AppendTime appends a time of day value.
+func (encoder *RowEncoder) AppendTime(val Time) {
+	encoder.arena = appendTime(val, encoder.arena)
+	encoder.end(types.BasicTime)
+}
+
+// AppendDuration appends a duration value.
+func (encoder *RowEncoder) AppendDuration(val Duration) {
+	encoder.arena = appendDuration(val, encoder.arena)
+	encoder.end(types.BasicDuration)
+}
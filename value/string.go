@@ -21,3 +21,25 @@ func EncodeString[T string | *string](val T, buf []byte) ([]byte, error) {
 		return buf, fmt.Errorf("unsupported string type: %T", val)
 	}
 }
+
+// DecodeString decodes a string from the front of the buffer.
+func DecodeString(buf []byte) (string, []byte, error) {
+	val, buf, err := decodeLength(buf)
+	return string(val), buf, err
+}
+
+// decodeLength splits a length-prefixed (uint64) value from the front of the
+// buffer.
+func decodeLength(buf []byte) ([]byte, []byte, error) {
+	size, buf, err := take(buf, 8)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	length := binary.BigEndian.Uint64(size)
+	if length > uint64(len(buf)) {
+		return nil, buf, ErrShortBuffer
+	}
+
+	return take(buf, int(length))
+}
@@ -0,0 +1,76 @@
+package value
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structFields caches the fields of struct types.
+var structFields sync.Map
+
+// StructField represents an exported struct field mapped onto a name.
+type StructField struct {
+	Name string
+	// Options represents the options following the name within the tag.
+	Options []string
+	// Index represents the index sequence of the field, including the
+	// indexes of the embedded structs it is promoted from.
+	Index []int
+	Field reflect.StructField
+}
+
+// StructFields returns the (cached) fields of the given struct type in their
+// declaration order. Exported fields are named using the `lunodb:"name,opts"`
+// tag, falling back to the field name. Fields tagged with `lunodb:"-"` are
+// ignored and embedded structs are flattened. The first field is kept when
+// multiple fields share a name.
+func StructFields(typ reflect.Type) []StructField {
+	cached, ok := structFields.Load(typ)
+	if ok {
+		return cached.([]StructField)
+	}
+
+	var fields []StructField
+	collectFields(typ, nil, make(map[string]struct{}), &fields)
+
+	cached, _ = structFields.LoadOrStore(typ, fields)
+	return cached.([]StructField)
+}
+
+func collectFields(typ reflect.Type, index []int, names map[string]struct{}, fields *[]StructField) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("lunodb")
+		if tag == "-" {
+			continue
+		}
+
+		path := append(append([]int{}, index...), i)
+		if field.Anonymous && !ok && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, path, names, fields)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		if _, exists := names[name]; exists {
+			continue
+		}
+
+		names[name] = struct{}{}
+		*fields = append(*fields, StructField{
+			Name:    name,
+			Options: strings.FieldsFunc(options, func(r rune) bool { return r == ',' }),
+			Index:   path,
+			Field:   field,
+		})
+	}
+}
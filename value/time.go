@@ -1,130 +1,196 @@
 package value
 
-// import (
-// 	"encoding/binary"
-// 	"fmt"
-// 	"time"
-
-// 	"github.com/cloudproud/lunodb.go/value/duration"
-// )
-
-// func NewDate(value time.Time, null bool) *Date {
-// 	return &Date{
-// 		value: value,
-// 		null:  null,
-// 	}
-// }
-
-// type Date struct {
-// 	value time.Time
-// 	null  bool
-// }
-
-// func (d Date) String() string {
-// 	if d.null {
-// 		return "date(nil)"
-// 	}
-
-// 	return fmt.Sprintf("date(%s)", d.value.Format(time.DateOnly))
-// }
-
-// func (d Date) Encode(buf []byte) ([]byte, error) {
-// 	if d.null {
-// 		return buf, nil
-// 	}
-
-// 	enc, err := d.value.MarshalBinary()
-// 	return append(buf, enc...), err
-// }
-
-// type Time struct {
-// 	value time.Time
-// 	null  bool
-// }
-
-// func NewTime(value time.Time, null bool) *Time {
-// 	return &Time{
-// 		value: value,
-// 		null:  null,
-// 	}
-// }
-
-// func (i Time) String() string {
-// 	if i.null {
-// 		return "time(nil)"
-// 	}
-
-// 	return fmt.Sprintf("time(%s)", i.value.Format(time.TimeOnly))
-// }
-
-// func (t Time) Encode(buf []byte) ([]byte, error) {
-// 	if t.null {
-// 		return buf, nil
-// 	}
-
-// 	enc, err := t.value.MarshalBinary()
-// 	return append(buf, enc...), err
-// }
-
-// type Timestamp struct {
-// 	value time.Time
-// 	null  bool
-// }
-
-// func NewTimestamp(value time.Time, null bool) *Timestamp {
-// 	return &Timestamp{
-// 		value: value,
-// 		null:  null,
-// 	}
-// }
-
-// func (i Timestamp) String() string {
-// 	if i.null {
-// 		return "timestamp(nil)"
-// 	}
-
-// 	return fmt.Sprintf("timestamp(%s)", i.value.Format(time.RFC3339Nano))
-// }
-
-// func (t Timestamp) Encode(buf []byte) ([]byte, error) {
-// 	if t.null {
-// 		return buf, nil
-// 	}
-
-// 	enc, err := t.value.MarshalBinary()
-// 	return append(buf, enc...), err
-// }
-
-// type Duration struct {
-// 	value duration.Duration
-// 	null  bool
-// }
-
-// func NewDuration(value duration.Duration, null bool) *Duration {
-// 	return &Duration{
-// 		value: value,
-// 		null:  null,
-// 	}
-// }
-
-// func (i Duration) String() string {
-// 	if i.null {
-// 		return "duration(nil)"
-// 	}
-
-// 	return fmt.Sprintf("duration(%s)", i.value.String())
-// }
-
-// func (i Duration) Encode(buf []byte) ([]byte, error) {
-// 	if i.null {
-// 		return buf, nil
-// 	}
-
-// 	nano, month, days := i.value.EncodeBigInt()
-// 	buf = append(buf, make([]byte, 24)...)
-// 	binary.BigEndian.PutUint64(buf[len(buf)-24:], uint64(days))
-// 	binary.BigEndian.PutUint64(buf[len(buf)-16:], uint64(month))
-// 	binary.BigEndian.PutUint64(buf[len(buf)-8:], uint64(nano.Int64()))
-
-// 	return buf, nil
-// }
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Date represents a calendar date. The date is taken from the location of
+// the underlying time, the time of day is ignored.
+type Date time.Time
+
+// NewDate constructs a new date for the given year, month and day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+func (date Date) String() string {
+	return time.Time(date).Format(time.DateOnly)
+}
+
+// Time represents a time of day. The time of day is taken from the location
+// of the underlying time, the date is ignored.
+type Time time.Time
+
+// NewTime constructs a new time of day.
+func NewTime(hour int, minute int, second int, nsec int) Time {
+	return Time(time.Date(1970, time.January, 1, hour, minute, second, nsec, time.UTC))
+}
+
+func (t Time) String() string {
+	return time.Time(t).Format("15:04:05.999999999")
+}
+
+// Duration represents a SQL interval. Months and days are kept apart from the
+// nanoseconds since their length varies.
+type Duration struct {
+	Months int64
+	Days   int64
+	Nanos  int64
+}
+
+func (duration Duration) String() string {
+	return fmt.Sprintf("%d months %d days %s", duration.Months, duration.Days, time.Duration(duration.Nanos))
+}
+
+// EncodeTimestamp encodes the given time as a timestamp. Timestamps are
+// normalized to UTC and encoded as the seconds since the Unix epoch (int64)
+// followed by the nanoseconds within the second (uint32).
+func EncodeTimestamp[T time.Time | *time.Time](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case time.Time:
+		return appendTimestamp(v, buf), nil
+	case *time.Time:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendTimestamp(*v, buf), nil
+	default:
+		return buf, fmt.Errorf("unsupported timestamp type: %T", val)
+	}
+}
+
+func appendTimestamp(t time.Time, buf []byte) []byte {
+	t = t.UTC()
+	buf = binary.BigEndian.AppendUint64(buf, uint64(t.Unix()))
+	return binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+}
+
+// EncodeDate encodes the given date as the number of days since the Unix
+// epoch (int32).
+func EncodeDate[T Date | *Date](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case Date:
+		return appendDate(v, buf), nil
+	case *Date:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendDate(*v, buf), nil
+	default:
+		return buf, fmt.Errorf("unsupported date type: %T", val)
+	}
+}
+
+func appendDate(date Date, buf []byte) []byte {
+	year, month, day := time.Time(date).Date()
+	days := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return binary.BigEndian.AppendUint32(buf, uint32(int32(days)))
+}
+
+// EncodeTime encodes the given time of day as the number of nanoseconds since
+// midnight (int64).
+func EncodeTime[T Time | *Time](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case Time:
+		return appendTime(v, buf), nil
+	case *Time:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendTime(*v, buf), nil
+	default:
+		return buf, fmt.Errorf("unsupported time type: %T", val)
+	}
+}
+
+func appendTime(t Time, buf []byte) []byte {
+	hour, minute, second := time.Time(t).Clock()
+	nanos := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second + time.Duration(time.Time(t).Nanosecond())
+	return binary.BigEndian.AppendUint64(buf, uint64(nanos))
+}
+
+// EncodeDuration encodes the given duration as the number of days (int64),
+// months (int64) and nanoseconds (int64). A time.Duration is encoded as
+// nanoseconds only.
+func EncodeDuration[T Duration | *Duration | time.Duration | *time.Duration](val T, buf []byte) ([]byte, error) {
+	switch v := any(val).(type) {
+	case Duration:
+		return appendDuration(v, buf), nil
+	case *Duration:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendDuration(*v, buf), nil
+	case time.Duration:
+		return appendDuration(Duration{Nanos: int64(v)}, buf), nil
+	case *time.Duration:
+		if v == nil {
+			return buf, nil
+		}
+
+		return appendDuration(Duration{Nanos: int64(*v)}, buf), nil
+	default:
+		return buf, fmt.Errorf("unsupported duration type: %T", val)
+	}
+}
+
+func appendDuration(duration Duration, buf []byte) []byte {
+	buf = binary.BigEndian.AppendUint64(buf, uint64(duration.Days))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(duration.Months))
+	return binary.BigEndian.AppendUint64(buf, uint64(duration.Nanos))
+}
+
+// DecodeTimestamp decodes a timestamp from the front of the buffer. The
+// timestamp is returned in UTC.
+func DecodeTimestamp(buf []byte) (time.Time, []byte, error) {
+	val, buf, err := take(buf, 12)
+	if err != nil {
+		return time.Time{}, buf, err
+	}
+
+	seconds := int64(binary.BigEndian.Uint64(val))
+	nanos := int64(binary.BigEndian.Uint32(val[8:]))
+	return time.Unix(seconds, nanos).UTC(), buf, nil
+}
+
+// DecodeDate decodes a date from the front of the buffer.
+func DecodeDate(buf []byte) (Date, []byte, error) {
+	days, buf, err := DecodeInt32(buf)
+	if err != nil {
+		return Date{}, buf, err
+	}
+
+	return Date(time.Unix(int64(days)*86400, 0).UTC()), buf, nil
+}
+
+// DecodeTime decodes a time of day from the front of the buffer.
+func DecodeTime(buf []byte) (Time, []byte, error) {
+	nanos, buf, err := DecodeInt64(buf)
+	if err != nil {
+		return Time{}, buf, err
+	}
+
+	return Time(time.Unix(0, nanos).UTC()), buf, nil
+}
+
+// DecodeDuration decodes a duration from the front of the buffer.
+func DecodeDuration(buf []byte) (duration Duration, _ []byte, err error) {
+	duration.Days, buf, err = DecodeInt64(buf)
+	if err != nil {
+		return duration, buf, err
+	}
+
+	duration.Months, buf, err = DecodeInt64(buf)
+	if err != nil {
+		return duration, buf, err
+	}
+
+	duration.Nanos, buf, err = DecodeInt64(buf)
+	return duration, buf, err
+}
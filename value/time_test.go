@@ -0,0 +1,62 @@
+package value
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestEncodeTemporalWireFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      any
+		expected []byte
+	}{
+		{
+			name:     "timestamp",
+			val:      time.Date(1970, 1, 1, 1, 0, 0, 5, time.FixedZone("CET", 3600)),
+			expected: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5},
+		},
+		{
+			name:     "date",
+			val:      NewDate(1970, 1, 3),
+			expected: []byte{0, 0, 0, 2},
+		},
+		{
+			name:     "time",
+			val:      NewTime(0, 0, 1, 2),
+			expected: []byte{0, 0, 0, 0, 0x3b, 0x9a, 0xca, 0x02},
+		},
+		{
+			name: "duration",
+			val:  Duration{Months: 1, Days: 2, Nanos: 3},
+			expected: []byte{
+				0, 0, 0, 0, 0, 0, 0, 2, // days
+				0, 0, 0, 0, 0, 0, 0, 1, // months
+				0, 0, 0, 0, 0, 0, 0, 3, // nanoseconds
+			},
+		},
+		{
+			name: "negative duration",
+			val:  -time.Nanosecond,
+			expected: []byte{
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, buf, err := Encode(test.val, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !bytes.Equal(buf, test.expected) {
+				t.Errorf("unexpected encoding: %x, expected %x", buf, test.expected)
+			}
+		})
+	}
+}
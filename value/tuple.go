@@ -0,0 +1,121 @@
+package value
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto/types"
+	"github.com/cloudproud/lunodb.go/types"
+)
+
+// Tuple represents an ordered list of values such as a (lat, lon) pair. NULL
+// items are represented as nil.
+type Tuple []any
+
+// Field represents a single named field within a record.
+type Field struct {
+	Name  string
+	Value any
+}
+
+// Record represents an ordered list of fields. Field names are not part of
+// the wire format, records are encoded and typed by the position of their
+// fields.
+type Record []Field
+
+// EncodeTuple encodes the given tuple. Tuples are encoded as the number of
+// items (uint32) followed by a length-prefixed (uint32) frame for every item.
+// NULL items are encoded as an empty frame. The returned type describes the
+// type of every item.
+func EncodeTuple(val Tuple, buf []byte) (*lunopb.Type, []byte, error) {
+	items, buf, err := encodeItems(len(val), func(index int) any { return val[index] }, buf)
+	if err != nil {
+		return types.BasicTuple, buf, err
+	}
+
+	return types.NewTuple(items...), buf, nil
+}
+
+// EncodeRecord encodes the given record using the same layout as tuples. The
+// returned type describes the type of every field.
+func EncodeRecord(val Record, buf []byte) (*lunopb.Type, []byte, error) {
+	items, buf, err := encodeItems(len(val), func(index int) any { return val[index].Value }, buf)
+	if err != nil {
+		return types.BasicRecord, buf, err
+	}
+
+	return types.NewRecord(items...), buf, nil
+}
+
+func encodeItems(count int, item func(int) any, buf []byte) ([]*lunopb.Type, []byte, error) {
+	offset := len(buf)
+	items := make([]*lunopb.Type, count)
+
+	buf = binary.BigEndian.AppendUint32(buf, uint32(count))
+	for index := range count {
+		frame := len(buf)
+		buf = append(buf, 0, 0, 0, 0)
+
+		var err error
+		items[index], buf, err = Encode(item(index), buf)
+		if err != nil {
+			return nil, buf[:offset], fmt.Errorf("item %d: %w", index, err)
+		}
+
+		binary.BigEndian.PutUint32(buf[frame:], uint32(len(buf)-frame-4))
+	}
+
+	return items, buf, nil
+}
+
+// encodeStruct encodes the exported fields of the given reflected struct as a
+// record. The fields are resolved using StructFields.
+func encodeStruct(rv reflect.Value, buf []byte) (*lunopb.Type, []byte, error) {
+	fields := StructFields(rv.Type())
+	record := make(Record, len(fields))
+	for index, field := range fields {
+		record[index] = Field{Name: field.Name, Value: rv.FieldByIndex(field.Index).Interface()}
+	}
+
+	return EncodeRecord(record, buf)
+}
+
+// DecodeTuple decodes a tuple of the given item types from the front of the
+// buffer. NULL items are decoded as nil.
+func DecodeTuple(items []*lunopb.Type, buf []byte) (Tuple, []byte, error) {
+	count, buf, err := DecodeUint32(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	if int(count) != len(items) {
+		return nil, buf, fmt.Errorf("tuple contains %d items while %d are declared", count, len(items))
+	}
+
+	result := make(Tuple, count)
+	for index := range result {
+		result[index], buf, err = decodeFrame(items[index], buf)
+		if err != nil {
+			return nil, buf, fmt.Errorf("tuple item %d: %w", index, err)
+		}
+	}
+
+	return result, buf, nil
+}
+
+// DecodeRecord decodes a record of the given field types from the front of
+// the buffer. Field names are not part of the wire format and are left empty.
+func DecodeRecord(items []*lunopb.Type, buf []byte) (Record, []byte, error) {
+	tuple, buf, err := DecodeTuple(items, buf)
+	if err != nil {
+		return nil, buf, err
+	}
+
+	result := make(Record, len(tuple))
+	for index, item := range tuple {
+		result[index].Value = item
+	}
+
+	return result, buf, nil
+}
@@ -1,7 +1,9 @@
 package value
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strings"
 )
 
 func EncodeUUID[T [16]byte](val T, buf []byte) ([]byte, error) {
@@ -12,3 +14,29 @@ func EncodeUUID[T [16]byte](val T, buf []byte) ([]byte, error) {
 		return buf, fmt.Errorf("unsupported uuid type: %T", val)
 	}
 }
+
+// ParseUUID parses the given canonical textual representation of a UUID
+// (e.g. 123e4567-e89b-12d3-a456-426614174000).
+func ParseUUID(val string) (uuid [16]byte, err error) {
+	if len(val) != 36 || val[8] != '-' || val[13] != '-' || val[18] != '-' || val[23] != '-' {
+		return uuid, fmt.Errorf("invalid uuid: %q", val)
+	}
+
+	_, err = hex.Decode(uuid[:], []byte(strings.ReplaceAll(val, "-", "")))
+	if err != nil {
+		return uuid, fmt.Errorf("invalid uuid: %q", val)
+	}
+
+	return uuid, nil
+}
+
+// DecodeUUID decodes an UUID from the front of the buffer.
+func DecodeUUID(buf []byte) (uuid [16]byte, _ []byte, err error) {
+	val, buf, err := take(buf, 16)
+	if err != nil {
+		return uuid, buf, err
+	}
+
+	copy(uuid[:], val)
+	return uuid, buf, nil
+}
@@ -1,3 +1,44 @@
+// Package value encodes Go values into the LunoDB row wire format.
+//
+// Every column value within a row is encoded as a single frame. NULL values
+// are encoded as an empty frame while every non-NULL value is encoded as at
+// least a single byte, allowing a NULL value to be told apart from an empty
+// value such as an empty string. All integers are encoded big-endian.
+//
+//	Bool       1 byte (0 or 1)
+//	Int*       fixed width two's complement integer
+//	Uint*      fixed width unsigned integer
+//	Float*     IEEE 754 binary representation
+//	String     length (uint64), UTF-8 bytes
+//	Bytes      length (uint64), bytes
+//	UUID       16 bytes
+//	Inet       address bytes (4 or 16), prefix length (1 byte), host addresses
+//	           are encoded as a /32 or /128 prefix
+//	Timestamp  seconds since the Unix epoch in UTC (int64), nanoseconds (uint32)
+//	Date       days since the Unix epoch (int32)
+//	Time       nanoseconds since midnight (int64)
+//	Duration   days (int64), months (int64), nanoseconds (int64), matching
+//	           the interval layout decoded by Stargate
+//	Array      element count (uint32), per element: frame length (uint32), frame
+//	Object     member count (uint32), per member in sorted key order:
+//	           key length (uint32), key, type descriptor length (uint32),
+//	           protobuf encoded type descriptor, frame length (uint32), frame
+//	Tuple      item count (uint32), per item: frame length (uint32), frame
+//	Record     field count (uint32), per field: frame length (uint32), frame
+//
+// Decimals (Decimal, big.Rat and big.Int) are typed and encoded as their exact
+// decimal string since the type system has no decimal kind. NOTE: this is a
+// stopgap, decimals can not be told apart from text on the wire and are not
+// checked against a declared precision. It is expected to change once the API
+// carries a decimal kind with precision and scale.
+//
+// Other types could be encoded by implementing Encoder, Valuer or
+// driver.Valuer, or by registering an encoder through Register. TypeOf never
+// encodes values, custom types are typed as any unless registered or
+// implementing Typer.
+//
+// Structs which are not encoded otherwise are encoded as records of their
+// exported fields.
 package value
 
 //go:generate go run ./cmd/encoder
@@ -0,0 +1,454 @@
+package lunodbgo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/planutil"
+	"github.com/cloudproud/lunodb.go/types"
+	"github.com/cloudproud/lunodb.go/value"
+	"go.uber.org/zap"
+)
+
+// BatchOptions defines how rows written during a scan are buffered before
+// being handed to the sender. A batch is flushed once any of the configured
+// bounds has been reached.
+//
+// NOTE: batching only buffers the sends, every row is still sent as its own
+// message since the Row message carries a single row. Coalescing rows into a
+// single message requires a multi-row message in the Stargate API.
+type BatchOptions struct {
+	// MaxRows is the maximum number of rows within a single batch.
+	MaxRows int
+	// MaxBytes is the maximum number of encoded bytes within a single batch.
+	MaxBytes int
+	// FlushInterval is the maximum duration rows are buffered before being
+	// flushed. Zero disables interval based flushing.
+	FlushInterval time.Duration
+}
+
+// DefaultBatchOptions represents the batch options used when no options have
+// been configured.
+var DefaultBatchOptions = BatchOptions{
+	MaxRows:       256,
+	MaxBytes:      1 << 20,
+	FlushInterval: 100 * time.Millisecond,
+}
+
+// Validate returns an error if the batch options are invalid.
+func (options BatchOptions) Validate() error {
+	if options.MaxRows < 1 {
+		return fmt.Errorf("invalid max rows: %d", options.MaxRows)
+	}
+
+	if options.MaxBytes < 1 {
+		return fmt.Errorf("invalid max bytes: %d", options.MaxBytes)
+	}
+
+	if options.FlushInterval < 0 {
+		return fmt.Errorf("invalid flush interval: %s", options.FlushInterval)
+	}
+
+	return nil
+}
+
+// statementWriter encodes and buffers the rows written by a handler during a
+// single statement. Buffered rows are handed to the sender as a single batch
+// once the batch is full, the flush interval expired or Flush is called.
+//
+// Rows passed to Write and WriteRow contain the projected columns, rows passed
+// to WriteFull contain all table columns in their declared order and are
+// reduced to the projected columns before being encoded.
+//
+// NOTE: values are encoded into an arena shared by all rows within a batch,
+// and row frames and messages are taken from shared slabs, to avoid
+// allocations per row. These are replaced once the batch has been handed to
+// the sender since the sent messages still reference them.
+type statementWriter struct {
+	ctx     context.Context
+	id      uint32
+	sender  *sender
+	options BatchOptions
+	logger  *zap.Logger
+	table   *Table
+	columns []*Column
+	names   []string
+	project []int
+	width   int
+
+	mu       sync.Mutex
+	batch    []*lunopb.ConnectorResponse
+	arena    []byte
+	frames   [][]byte
+	messages []rowMessage
+	size     int
+	timer    *time.Timer
+	err      error
+}
+
+// rowMessage holds all messages required to send a single row, allowing them
+// to be allocated at once.
+type rowMessage struct {
+	response lunopb.ConnectorResponse
+	state    lunopb.ConnectorResponse_ExecuteStatement
+	execute  lunopb.ExecuteStatementResponse
+	data     lunopb.ExecuteStatementResponse_Data
+	row      lunopb.Row
+}
+
+func newStatementWriter(ctx context.Context, id uint32, sender *sender, options BatchOptions, logger *zap.Logger) *statementWriter {
+	return &statementWriter{
+		ctx:     ctx,
+		id:      id,
+		sender:  sender,
+		options: options,
+		logger:  logger,
+		batch:   make([]*lunopb.ConnectorResponse, 0, options.MaxRows),
+	}
+}
+
+// Write encodes the given row containing the projected columns and appends it
+// to the current batch.
+func (writer *statementWriter) Write(ctx context.Context, values []any) error {
+	if writer.columns != nil && len(values) != len(writer.columns) {
+		return fmt.Errorf("row contains %d values while %d columns are projected", len(values), len(writer.columns))
+	}
+
+	return writer.write(ctx, values, nil, len(values))
+}
+
+// WriteFull encodes the projected columns of the given row containing all
+// table columns in their declared order and appends it to the current batch.
+// The row is written as is if the projection is unknown.
+func (writer *statementWriter) WriteFull(ctx context.Context, values []any) error {
+	if writer.project == nil {
+		return writer.Write(ctx, values)
+	}
+
+	if len(values) != writer.width {
+		return fmt.Errorf("row contains %d values while the table declares %d columns", len(values), writer.width)
+	}
+
+	return writer.write(ctx, values, writer.project, len(writer.project))
+}
+
+// write encodes the given number of values at the projected positions of the
+// given row and appends them to the current batch.
+func (writer *statementWriter) write(ctx context.Context, values []any, project []int, size int) error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if writer.err != nil {
+		return writer.err
+	}
+
+	offset := len(writer.arena)
+	row := writer.row(size)
+	for index := range row {
+		start := len(writer.arena)
+		arena, err := writer.encode(index, values[field(project, index)], writer.arena)
+		if err != nil {
+			writer.arena = writer.arena[:offset]
+			return err
+		}
+
+		writer.arena = arena
+		row[index] = writer.frame(start)
+	}
+
+	return writer.append(ctx, row, len(writer.arena)-offset)
+}
+
+// WriteRow appends the given pre-encoded row to the current batch. The value
+// types are validated against the projected columns, the values are not
+// coerced.
+func (writer *statementWriter) WriteRow(ctx context.Context, encoder *value.RowEncoder) error {
+	if writer.columns != nil && encoder.Len() != len(writer.columns) {
+		return fmt.Errorf("row contains %d values while %d columns are projected", encoder.Len(), len(writer.columns))
+	}
+
+	for index, column := range writer.columns {
+		if column == nil {
+			continue
+		}
+
+		if len(encoder.Frame(index)) == 0 {
+			if !column.Nullable {
+				return fmt.Errorf("column %q: null value in non-nullable column", column.Name)
+			}
+
+			continue
+		}
+
+		if !types.Assignable(column.Type, encoder.Type(index)) {
+			return fmt.Errorf("column %q: cannot use %s as %s", column.Name, types.Name(encoder.Type(index)), types.Name(column.Type))
+		}
+	}
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if writer.err != nil {
+		return writer.err
+	}
+
+	offset := len(writer.arena)
+	row := writer.row(encoder.Len())
+	for index := range row {
+		start := len(writer.arena)
+		writer.arena = append(writer.arena, encoder.Frame(index)...)
+		row[index] = writer.frame(start)
+	}
+
+	return writer.append(ctx, row, len(writer.arena)-offset)
+}
+
+// field returns the position of the value at the given index within a row
+// using the given projection.
+func field(project []int, index int) int {
+	if project == nil {
+		return index
+	}
+
+	return project[index]
+}
+
+// row returns a row of the given number of frames sliced from the slab.
+func (writer *statementWriter) row(size int) [][]byte {
+	if cap(writer.frames)-len(writer.frames) < size {
+		writer.frames = make([][]byte, 0, size*writer.options.MaxRows)
+	}
+
+	offset := len(writer.frames)
+	writer.frames = writer.frames[:offset+size]
+	return writer.frames[offset : offset+size : offset+size]
+}
+
+// frame returns the frame encoded into the arena from the given offset. NULL
+// values are returned as nil.
+func (writer *statementWriter) frame(start int) []byte {
+	end := len(writer.arena)
+	if start == end {
+		return nil
+	}
+
+	return writer.arena[start:end:end]
+}
+
+// append appends the given encoded row to the current batch and flushes the
+// batch once it is full. The writer lock should be held.
+func (writer *statementWriter) append(ctx context.Context, row [][]byte, size int) error {
+	writer.logger.Debug("writing row")
+
+	if len(writer.messages) == cap(writer.messages) {
+		writer.messages = make([]rowMessage, 0, writer.options.MaxRows)
+	}
+
+	writer.messages = writer.messages[:len(writer.messages)+1]
+	message := &writer.messages[len(writer.messages)-1]
+	message.row.Values = row
+	message.data.Data = &message.row
+	message.execute.Result = &message.data
+	message.state.ExecuteStatement = &message.execute
+	message.response.Id = writer.id
+	message.response.State = &message.state
+
+	writer.batch = append(writer.batch, &message.response)
+
+	writer.size += size
+	if len(writer.batch) >= writer.options.MaxRows || writer.size >= writer.options.MaxBytes {
+		return writer.flush(ctx)
+	}
+
+	if len(writer.batch) == 1 && writer.options.FlushInterval > 0 {
+		writer.timer = time.AfterFunc(writer.options.FlushInterval, writer.flushInterval)
+	}
+
+	return nil
+}
+
+// declaredTable returns the table targeted by the statement, false is
+// returned if the table is unknown.
+func (writer *statementWriter) declaredTable() (Table, bool) {
+	if writer.table == nil {
+		return Table{}, false
+	}
+
+	return *writer.table, true
+}
+
+// Columns returns the names of the columns projected by the statement plan in
+// order. Nil is returned if the projection is unknown.
+func (writer *statementWriter) Columns() []string {
+	return writer.names
+}
+
+// encode appends the given value of the column at the given index to the
+// buffer. Values are validated against the projected column type if the
+// column is known. NULL values are only accepted for nullable columns and are
+// encoded as an empty frame.
+func (writer *statementWriter) encode(index int, val any, buf []byte) ([]byte, error) {
+	var column *Column
+	if writer.columns != nil {
+		column = writer.columns[index]
+	}
+
+	if value.IsNull(val) {
+		if column != nil && !column.Nullable {
+			return buf, fmt.Errorf("column %q: null value in non-nullable column", column.Name)
+		}
+
+		return buf, nil
+	}
+
+	if column == nil {
+		_, buf, err := value.Encode(val, buf)
+		return buf, err
+	}
+
+	offset := len(buf)
+	buf, err := value.EncodeAs(column.Type, val, buf)
+	if err != nil {
+		return buf, fmt.Errorf("column %q: %w", column.Name, err)
+	}
+
+	// NOTE: custom types, such as an invalid sql.NullString, could encode NULL
+	if len(buf) == offset && !column.Nullable {
+		return buf, fmt.Errorf("column %q: null value in non-nullable column", column.Name)
+	}
+
+	return buf, nil
+}
+
+// Flush hands all buffered rows to the sender.
+func (writer *statementWriter) Flush(ctx context.Context) error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	if writer.err != nil {
+		return writer.err
+	}
+
+	return writer.flush(ctx)
+}
+
+// close flushes all buffered rows followed by the given final message. The
+// writer should not be used after it has been closed.
+func (writer *statementWriter) close(ctx context.Context, final *lunopb.ConnectorResponse) error {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	writer.stop()
+	batch := append(writer.batch, final)
+	writer.batch = nil
+	writer.arena = nil
+	writer.frames = nil
+	writer.messages = nil
+	writer.size = 0
+
+	return writer.sender.Send(ctx, batch...)
+}
+
+func (writer *statementWriter) flushInterval() {
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+
+	err := writer.flush(writer.ctx)
+	if err != nil && writer.err == nil {
+		writer.logger.Error("failed to flush rows", zap.Error(err))
+		writer.err = err
+	}
+}
+
+func (writer *statementWriter) flush(ctx context.Context) error {
+	writer.stop()
+	if len(writer.batch) == 0 {
+		return nil
+	}
+
+	batch := writer.batch
+	writer.batch = make([]*lunopb.ConnectorResponse, 0, writer.options.MaxRows)
+	writer.arena = make([]byte, 0, len(writer.arena))
+	writer.frames = nil
+	writer.messages = nil
+	writer.size = 0
+
+	return writer.sender.Send(ctx, batch...)
+}
+
+func (writer *statementWriter) stop() {
+	if writer.timer == nil {
+		return
+	}
+
+	writer.timer.Stop()
+	writer.timer = nil
+}
+
+// outputColumns resolves the columns projected by the given plan in order.
+// Projected expressions which are not table columns are returned as nil. Nil
+// is returned if the plan table could not be resolved.
+func outputColumns(plan *plan.Literal, tables Tables) []*Column {
+	names := planutil.Columns(plan)
+	if plan.GetFrom() == nil || len(names) == 0 {
+		return nil
+	}
+
+	table, ok := tables.Find(plan.From.Schema, plan.From.Table)
+	if !ok {
+		return nil
+	}
+
+	columns := make([]*Column, len(names))
+	for index, name := range names {
+		if name == "" {
+			continue
+		}
+
+		column, ok := table.Columns.Find(name)
+		if ok {
+			columns[index] = &column
+		}
+	}
+
+	return columns
+}
+
+// outputProjection resolves the positions of the columns projected by the
+// given plan within the table columns, together with the number of table
+// columns. Nil is returned if any of the projected expressions is not a table
+// column.
+func outputProjection(plan *plan.Literal, tables Tables) ([]int, int) {
+	names := outputNames(plan)
+	if plan.GetFrom() == nil || names == nil {
+		return nil, 0
+	}
+
+	table, ok := tables.Find(plan.From.Schema, plan.From.Table)
+	if !ok {
+		return nil, 0
+	}
+
+	project := positions(table.Columns, names)
+	if project == nil {
+		return nil, 0
+	}
+
+	return project, len(table.Columns)
+}
+
+// outputNames returns the names of the columns projected by the given plan in
+// order. Nil is returned if any of the projected expressions is not a column.
+func outputNames(plan *plan.Literal) []string {
+	names := planutil.Columns(plan)
+	if len(names) == 0 || slices.Contains(names, "") {
+		return nil
+	}
+
+	return names
+}
@@ -0,0 +1,268 @@
+package lunodbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lunopb "github.com/cloudproud/lunodb.api/proto"
+	"github.com/cloudproud/lunodb.api/proto/plan"
+	"github.com/cloudproud/lunodb.go/types"
+	"github.com/cloudproud/lunodb.go/value"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// discardStream is a stream discarding all sent messages.
+type discardStream struct {
+	grpc.ClientStream
+}
+
+func (stream *discardStream) Send(msg *lunopb.ConnectorResponse) error {
+	return nil
+}
+
+func (stream *discardStream) Recv() (*lunopb.ConnectorRequest, error) {
+	return nil, context.Canceled
+}
+
+func (stream *discardStream) CloseSend() error {
+	return nil
+}
+
+var weatherColumns = Columns{
+	{Name: "city", Type: types.BasicString},
+	{Name: "temperature", Type: types.BasicInt64},
+	{Name: "humidity", Type: types.BasicFloat64},
+}
+
+// newTestWriter constructs a statement writer projecting the weather columns
+// and sending to a running sender.
+func newTestWriter(tb testing.TB, stream stream) *statementWriter {
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(cancel)
+
+	sender := newSender(stream, DefaultSendQueueSize)
+	go sender.run(ctx) //nolint:errcheck
+
+	writer := newStatementWriter(ctx, 1, sender, DefaultBatchOptions, zap.NewNop())
+	for index := range weatherColumns {
+		writer.columns = append(writer.columns, &weatherColumns[index])
+		writer.names = append(writer.names, weatherColumns[index].Name)
+	}
+
+	return writer
+}
+
+// maxAllocsPerRow is the maximum number of allocations per written row,
+// allocations amortized over a batch are not counted.
+const maxAllocsPerRow = 0
+
+func TestStatementWriterAllocs(t *testing.T) {
+	ctx := context.Background()
+	writer := newTestWriter(t, &discardStream{})
+	row := []any{"Amsterdam", int64(1024), 80.5}
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		err := writer.Write(ctx, row)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	if allocs > maxAllocsPerRow {
+		t.Errorf("Write allocates %v times per row, expected at most %d", allocs, maxAllocsPerRow)
+	}
+
+	encoder := value.NewRowEncoder()
+	defer encoder.Release()
+
+	allocs = testing.AllocsPerRun(1000, func() {
+		encoder.Reset()
+		encoder.AppendString("Amsterdam")
+		encoder.AppendInt64(1024)
+		encoder.AppendFloat64(80.5)
+
+		err := writer.WriteRow(ctx, encoder)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	if allocs > maxAllocsPerRow {
+		t.Errorf("WriteRow allocates %v times per row, expected at most %d", allocs, maxAllocsPerRow)
+	}
+}
+
+func BenchmarkStatementWriterWrite(b *testing.B) {
+	ctx := context.Background()
+	writer := newTestWriter(b, &discardStream{})
+	row := []any{"Amsterdam", int64(1024), 80.5}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		err := writer.Write(ctx, row)
+		if err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+func BenchmarkStatementWriterWriteRow(b *testing.B) {
+	ctx := context.Background()
+	writer := newTestWriter(b, &discardStream{})
+
+	encoder := value.NewRowEncoder()
+	defer encoder.Release()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		encoder.Reset()
+		encoder.AppendString("Amsterdam")
+		encoder.AppendInt64(1024)
+		encoder.AppendFloat64(80.5)
+
+		err := writer.WriteRow(ctx, encoder)
+		if err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+func TestStatementWriterPermutation(t *testing.T) {
+	ctx := context.Background()
+	tables := Tables{{Name: "weather", Schema: "public", Columns: weatherColumns}}
+	literal := &plan.Literal{
+		From:    &plan.From{Schema: "public", Table: "weather"},
+		Columns: []*plan.Expression{columnExpr("humidity"), columnExpr("city"), columnExpr("temperature")},
+	}
+
+	writer := newTestWriter(t, &discardStream{})
+	writer.options.FlushInterval = 0
+	writer.columns = outputColumns(literal, tables)
+	writer.names = outputNames(literal)
+	writer.project, writer.width = outputProjection(literal, tables)
+
+	err := WriteFull(ctx, writer, []any{"Amsterdam", int64(12), 80.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = writer.Write(ctx, []any{60.5, "Utrecht", int64(14)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := [][]any{
+		{80.5, "Amsterdam", int64(12)},
+		{60.5, "Utrecht", int64(14)},
+	}
+
+	if len(writer.batch) != len(expected) {
+		t.Fatalf("unexpected batch size: %d", len(writer.batch))
+	}
+
+	for index, response := range writer.batch {
+		frames := response.GetExecuteStatement().GetData().GetValues()
+		if len(frames) != len(writer.columns) {
+			t.Fatalf("unexpected number of values: %d", len(frames))
+		}
+
+		for position, frame := range frames {
+			val, _, err := value.Decode(writer.columns[position].Type, frame)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if val != expected[index][position] {
+				t.Errorf("row %d: unexpected %s: %#v, expected %#v", index, writer.names[position], val, expected[index][position])
+			}
+		}
+	}
+
+	err = WriteFull(ctx, writer, []any{80.5, "Amsterdam"})
+	if err == nil {
+		t.Fatal("expected an error for a row not containing all table columns")
+	}
+}
+
+func TestStatementWriterFlush(t *testing.T) {
+	tests := map[string]struct {
+		options BatchOptions
+		rows    int
+		batches int
+	}{
+		"rows": {
+			options: BatchOptions{MaxRows: 2, MaxBytes: 1 << 20},
+			rows:    5,
+			batches: 2,
+		},
+		"bytes": {
+			options: BatchOptions{MaxRows: 100, MaxBytes: 24},
+			rows:    5,
+			batches: 2,
+		},
+		"unbounded": {
+			options: BatchOptions{MaxRows: 100, MaxBytes: 1 << 20},
+			rows:    5,
+			batches: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// NOTE: the sender is not running, flushed batches remain queued.
+			sender := newSender(newTestStream(ctx), 8)
+			writer := newStatementWriter(ctx, 1, sender, test.options, zap.NewNop())
+
+			for range test.rows {
+				err := writer.Write(ctx, []any{"Amsterdam"})
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			if len(sender.queue) != test.batches {
+				t.Fatalf("unexpected number of flushed batches: %d, expected %d", len(sender.queue), test.batches)
+			}
+
+			for range test.batches {
+				batch := <-sender.queue
+				if len(batch) != 2 {
+					t.Errorf("unexpected batch size: %d", len(batch))
+				}
+			}
+
+			err := writer.Flush(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(sender.queue) != 1 || len(<-sender.queue) != test.rows-2*test.batches {
+				t.Errorf("expected the remaining rows to be flushed")
+			}
+		})
+	}
+}
+
+func TestStatementWriterFlushInterval(t *testing.T) {
+	ctx := context.Background()
+	sender := newSender(newTestStream(ctx), 8)
+	writer := newStatementWriter(ctx, 1, sender, BatchOptions{MaxRows: 100, MaxBytes: 1 << 20, FlushInterval: 10 * time.Millisecond}, zap.NewNop())
+
+	err := writer.Write(ctx, []any{"Amsterdam"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case batch := <-sender.queue:
+		if len(batch) != 1 {
+			t.Errorf("unexpected batch size: %d", len(batch))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the buffered rows to be flushed once the interval expired")
+	}
+}